@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textlint"
+)
+
+// requestTimeout 单次请求的超时时间。发布以异步任务提交，请求本身很快返回，
+// local 定时发布提交时服务要先下载或复制图片，所以留出一些余量
+const requestTimeout = 2 * time.Minute
+
+// apiClient 调用 xiaohongshu-mcp 服务的 HTTP API，发布由服务中的 XiaohongshuService 完成。
+// 不在命令行进程中直接创建 XiaohongshuService：登录状态和浏览器数据目录属于正在运行的服务，
+// 两个进程同时打开同一账号的浏览器会互相干扰；通过服务的任务队列发布，也能和服务收到的其他发布依次执行
+type apiClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func newAPIClient(baseURL string) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// apiError 服务返回的错误响应
+type apiError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"error"`
+	Details    any    `json:"details"`
+}
+
+func (e *apiError) Error() string {
+	if e.Details != nil {
+		return fmt.Sprintf("%s: %s (%v)", e.Code, e.Message, e.Details)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// publishRequest POST /api/v1/publish 的请求体，以异步任务提交
+type publishRequest struct {
+	Title          string   `json:"title"`
+	Content        string   `json:"content"`
+	Images         []string `json:"images"`
+	Tags           []string `json:"tags,omitempty"`
+	ScheduleAt     string   `json:"schedule_at,omitempty"`
+	ScheduleMode   string   `json:"schedule_mode,omitempty"`
+	Visibility     string   `json:"visibility,omitempty"`
+	ContentFormat  string   `json:"content_format,omitempty"`
+	Async          bool     `json:"async"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty"`
+}
+
+// publishResponse 发布结果中命令行需要的字段
+type publishResponse struct {
+	Status        string `json:"status"`
+	PostID        string `json:"post_id"`
+	PostURL       string `json:"post_url"`
+	NoteIDUnknown bool   `json:"note_id_unknown"`
+}
+
+// 任务状态，与服务一致
+const (
+	jobScheduled = "scheduled"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCanceled  = "canceled"
+)
+
+// job 服务中的发布任务
+type job struct {
+	ID       string          `json:"job_id"`
+	Status   string          `json:"status"`
+	Progress *jobProgress    `json:"progress"`
+	Result   json.RawMessage `json:"result"`
+	Error    string          `json:"error"`
+
+	// Unconfirmed 任务没有成功，但笔记可能已经发布，不能直接重新提交
+	Unconfirmed bool `json:"unconfirmed"`
+}
+
+// jobProgress 任务当前执行到的步骤
+type jobProgress struct {
+	Step    string `json:"step"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+}
+
+// finished 任务是否已结束
+func (j *job) finished() bool {
+	return j.Status == jobSucceeded || j.Status == jobFailed || j.Status == jobCanceled
+}
+
+// lintRequest POST /api/v1/lint 的请求体
+type lintRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// lintResponse 敏感词检查结果
+type lintResponse struct {
+	Blocked  bool               `json:"blocked"`
+	Findings []textlint.Finding `json:"findings"`
+}
+
+// loginStatus 登录状态
+type loginStatus struct {
+	IsLoggedIn bool   `json:"is_logged_in"`
+	Username   string `json:"username"`
+}
+
+func (c *apiClient) checkLogin(ctx context.Context) (*loginStatus, error) {
+	var status loginStatus
+	if err := c.do(ctx, http.MethodGet, "/api/v1/login/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *apiClient) lint(ctx context.Context, req *lintRequest) (*lintResponse, error) {
+	var resp lintResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/lint", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// submitPublish 提交发布任务。相同 idempotency_key 重复提交时返回第一次提交的任务及其当前状态
+func (c *apiClient) submitPublish(ctx context.Context, req *publishRequest) (*job, error) {
+	req.Async = true
+	var j job
+	if err := c.do(ctx, http.MethodPost, "/api/v1/publish", req, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (c *apiClient) getJob(ctx context.Context, id string) (*job, error) {
+	var j job
+	if err := c.do(ctx, http.MethodGet, "/api/v1/jobs/"+url.PathEscape(id), nil, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// do 发送请求并把响应中的 data 解析到 out
+func (c *apiClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求服务失败，请确认服务已启动: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == "" {
+			return fmt.Errorf("服务返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+		return apiErr
+	}
+
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
@@ -0,0 +1,366 @@
+// publish 按清单目录批量发布图文笔记。
+// 每篇笔记一个 YAML 或 JSON 清单文件，先检查全部清单，都通过后再逐篇提交到 xiaohongshu-mcp 服务的发布任务队列，
+// 轮询任务直到发布完成，每篇之间间隔一段时间，结果写入结果文件，失败后可以用 -resume 从中断处继续。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textlint"
+)
+
+// defaultResultsFile 默认的结果文件名，保存在清单目录中
+const defaultResultsFile = "publish-results.json"
+
+// options 命令行参数
+type options struct {
+	dir              string
+	server           string
+	results          string
+	resume           bool
+	check            bool
+	retryUnconfirmed bool
+	interval         time.Duration
+	jitter           time.Duration
+	timeout          time.Duration
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.dir, "dir", "", "清单目录，每篇笔记一个 .yaml/.yml/.json 文件，按文件名顺序发布")
+	flag.StringVar(&opts.server, "server", "http://localhost:18060", "xiaohongshu-mcp 服务地址")
+	flag.StringVar(&opts.results, "results", "", "结果文件路径，为空时使用清单目录下的 "+defaultResultsFile)
+	flag.BoolVar(&opts.resume, "resume", false, "从上次的结果文件继续，跳过已发布的笔记，重试失败的笔记")
+	flag.BoolVar(&opts.retryUnconfirmed, "retry-unconfirmed", false, "与 -resume 一起使用：已确认没有发布的 unconfirmed 笔记换一个 idempotency_key 重新提交")
+	flag.BoolVar(&opts.check, "check", false, "只检查清单，不发布")
+	flag.DurationVar(&opts.interval, "interval", 5*time.Minute, "两篇笔记之间的间隔")
+	flag.DurationVar(&opts.jitter, "jitter", time.Minute, "在间隔上随机增加 0 至 jitter 的时间，避免发布时间过于规律")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Minute, "等待单篇笔记发布完成的最长时间，超时后任务仍在服务中执行，-resume 时继续等待")
+	flag.Parse()
+
+	if opts.dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if opts.results == "" {
+		opts.results = filepath.Join(opts.dir, defaultResultsFile)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, opts); err != nil {
+		logrus.Fatalf("%v", err)
+	}
+}
+
+func run(ctx context.Context, opts options) error {
+	notes, err := loadManifests(opts.dir, opts.results)
+	if err != nil {
+		return err
+	}
+
+	previous, err := loadResults(opts.results)
+	if err != nil {
+		return err
+	}
+	if previous != nil && !opts.resume && !opts.check {
+		return fmt.Errorf("结果文件已存在: %s。继续上次的发布请加 -resume，重新发布全部笔记请先删除结果文件", opts.results)
+	}
+	if !opts.resume {
+		previous = nil
+	}
+	res := newResults(opts.results, opts.dir, notes, previous)
+
+	todo, err := pendingNotes(notes, res, opts.retryUnconfirmed)
+	if err != nil {
+		return err
+	}
+	if len(todo) == 0 {
+		logrus.Infof("全部 %d 篇笔记都已发布", len(notes))
+		return nil
+	}
+
+	client := newAPIClient(opts.server)
+	if err := checkNotes(ctx, client, todo); err != nil {
+		return err
+	}
+	if opts.check {
+		logrus.Infof("%d 篇笔记检查通过", len(todo))
+		return nil
+	}
+
+	status, err := client.checkLogin(ctx)
+	if err != nil {
+		return fmt.Errorf("检查登录状态失败: %w", err)
+	}
+	if !status.IsLoggedIn {
+		return errors.New("服务未登录，请先登录后再发布")
+	}
+	logrus.Infof("当前账号: %s，待发布 %d 篇，已发布 %d 篇", status.Username, len(todo), res.count(statusPublished))
+
+	if err := res.save(); err != nil {
+		return err
+	}
+	for i, n := range todo {
+		if i > 0 {
+			if err := wait(ctx, pace(opts.interval, opts.jitter)); err != nil {
+				return fmt.Errorf("已停止，使用 -resume 继续: %w", err)
+			}
+		}
+
+		r := res.get(n.File)
+		if err := publishNote(ctx, client, n, r, res.save, opts.timeout); err != nil {
+			if saveErr := res.save(); saveErr != nil {
+				logrus.Errorf("%v", saveErr)
+			}
+			return fmt.Errorf("%s 发布失败，已停止（结果见 %s），处理后使用 -resume 继续: %w", n.File, opts.results, err)
+		}
+		if err := res.save(); err != nil {
+			return err
+		}
+		if r.Status == statusScheduled {
+			logrus.Infof("[%d/%d] %s 已交给服务定时发布，发布时间 %s，任务 %s", i+1, len(todo), n.File, r.ScheduleAt, r.JobID)
+			continue
+		}
+		if r.NoteIDUnknown {
+			logrus.Warnf("[%d/%d] %s 发布成功，但没能获取笔记 ID，请到创作者中心确认", i+1, len(todo), n.File)
+			continue
+		}
+		logrus.Infof("[%d/%d] %s 发布成功: %s", i+1, len(todo), n.File, r.PostURL)
+	}
+
+	logrus.Infof("全部完成，共发布 %d 篇，服务定时发布 %d 篇，结果见 %s", res.count(statusPublished), res.count(statusScheduled), opts.results)
+	return nil
+}
+
+// pendingNotes 返回还需要发布的笔记。已发布和已交给服务定时发布的笔记不再检查和发布；
+// 有可能已经发布的 unconfirmed 笔记需要先人工确认，没有 retryUnconfirmed 时返回错误，不发布任何笔记
+func pendingNotes(notes []*note, res *results, retryUnconfirmed bool) ([]*note, error) {
+	var todo, unconfirmed []*note
+	for _, n := range notes {
+		r := res.get(n.File)
+		switch {
+		case r.Status == statusUnconfirmed:
+			unconfirmed = append(unconfirmed, n)
+		case r.done():
+			if r.Fingerprint != n.Fingerprint {
+				logrus.Warnf("%s 已发布，但清单在发布后被修改，不会重新发布", n.File)
+			}
+			continue
+		}
+		todo = append(todo, n)
+	}
+
+	if len(unconfirmed) == 0 {
+		return todo, nil
+	}
+	if !retryUnconfirmed {
+		for _, n := range unconfirmed {
+			logrus.Errorf("%s 已提交，但无法确认是否已发布（任务 %s）", n.File, res.get(n.File).JobID)
+		}
+		return nil, fmt.Errorf("%d 篇笔记可能已经发布，请到创作者中心确认：已发布的把结果文件中的 status 改为 published；"+
+			"都没有发布时加 -retry-unconfirmed 重新提交", len(unconfirmed))
+	}
+	// 已确认没有发布，换一个 idempotency_key 重新提交
+	for _, n := range unconfirmed {
+		r := res.get(n.File)
+		r.Retries++
+		r.Status = statusFailed
+	}
+	return todo, nil
+}
+
+// checkNotes 发布前检查全部清单，有问题时一次列出所有问题，不发布任何笔记
+func checkNotes(ctx context.Context, client *apiClient, notes []*note) error {
+	now := time.Now()
+	for _, n := range notes {
+		n.validate(now)
+		if len(n.Problems) > 0 {
+			continue
+		}
+
+		content, tags := n.text()
+		lint, err := client.lint(ctx, &lintRequest{Title: n.Manifest.Title, Content: content, Tags: tags})
+		if err != nil {
+			return fmt.Errorf("敏感词检查失败: %w", err)
+		}
+		for _, f := range lint.Findings {
+			if lint.Blocked && f.Severity == textlint.SeverityHigh {
+				n.Problems = append(n.Problems, fmt.Sprintf("%s 中有敏感词「%s」", f.Field, f.Text))
+				continue
+			}
+			logrus.Warnf("%s: %s 中有敏感词「%s」（%s）", n.File, f.Field, f.Text, f.Severity)
+		}
+	}
+
+	var invalid int
+	for _, n := range notes {
+		if len(n.Problems) == 0 {
+			continue
+		}
+		invalid++
+		for _, problem := range n.Problems {
+			logrus.Errorf("%s: %s", n.File, problem)
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d 篇笔记的清单有问题，修改后重新运行，没有发布任何笔记", invalid)
+	}
+	return nil
+}
+
+// jobPollInterval 查询发布任务状态的间隔
+var jobPollInterval = 3 * time.Second
+
+// publishNote 提交一篇笔记的发布任务并等待结果，更新 r。
+// 发布在服务的任务队列中执行，命令行只轮询任务状态，发布时间再长也不会因为请求超时而中断发布。
+// 提交后先调用 save 记下任务，命令行中途退出时 -resume 会用同一个 idempotency_key 拿到这个任务继续等待
+func publishNote(ctx context.Context, client *apiClient, n *note, r *noteResult, save func() error, timeout time.Duration) error {
+	m := n.Manifest
+	r.Title = m.Title
+	r.Fingerprint = n.Fingerprint
+	r.ScheduleAt = m.ScheduleAt
+
+	fail := func(err error) error {
+		now := time.Now()
+		r.Status = statusFailed
+		r.Error = err.Error()
+		r.FinishedAt = &now
+		return err
+	}
+
+	// 前面的笔记和间隔占用了时间，平台定时发布的时间可能已经超出范围
+	if err := n.checkSchedule(time.Now()); err != nil {
+		return fail(fmt.Errorf("%w，修改 schedule_at 或设置 schedule_mode: local 后继续", err))
+	}
+
+	r.Attempts++
+	j, err := client.submitPublish(ctx, &publishRequest{
+		Title:          m.Title,
+		Content:        m.Body,
+		Images:         m.Images,
+		Tags:           m.Tags,
+		ScheduleAt:     m.ScheduleAt,
+		ScheduleMode:   m.ScheduleMode,
+		Visibility:     m.Visibility,
+		ContentFormat:  m.ContentFormat,
+		IdempotencyKey: n.idempotencyKey(r.Retries),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if j.ID == r.JobID {
+		logrus.Infof("%s 继续等待上次提交的任务 %s", n.File, j.ID)
+	}
+	r.JobID = j.ID
+	if err := save(); err != nil {
+		return err
+	}
+
+	if j.Status == jobScheduled {
+		now := time.Now()
+		r.Status = statusScheduled
+		r.Error = ""
+		r.FinishedAt = &now
+		return nil
+	}
+
+	j, err = waitJob(ctx, client, n.File, j, timeout)
+	if err != nil {
+		return fail(fmt.Errorf("等待任务 %s 结束失败，任务仍在服务中执行，使用 -resume 继续等待: %w", j.ID, err))
+	}
+	if j.Status != jobSucceeded {
+		err := fmt.Errorf("任务 %s %s: %s", j.ID, j.Status, j.Error)
+		if j.Unconfirmed {
+			// 笔记可能已经发布，保留原来的 idempotency_key，需要人工确认后再决定是否重新提交
+			fail(err)
+			r.Status = statusUnconfirmed
+			return fmt.Errorf("%w，笔记可能已经发布，请到创作者中心确认", err)
+		}
+		// 任务已经结束，-resume 时换一个 idempotency_key 重新提交
+		r.Retries++
+		return fail(err)
+	}
+
+	var resp publishResponse
+	if err := json.Unmarshal(j.Result, &resp); err != nil {
+		return fail(fmt.Errorf("解析任务 %s 的结果失败: %w", j.ID, err))
+	}
+	now := time.Now()
+	r.Status = statusPublished
+	r.Error = ""
+	r.PostID = resp.PostID
+	r.PostURL = resp.PostURL
+	r.NoteIDUnknown = resp.NoteIDUnknown
+	r.FinishedAt = &now
+	return nil
+}
+
+// waitJob 轮询任务直到结束。超过 timeout 或 ctx 取消时返回错误，任务仍在服务中继续执行
+func waitJob(ctx context.Context, client *apiClient, file string, j *job, timeout time.Duration) (*job, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var step string
+	for !j.finished() {
+		if j.Progress != nil && j.Progress.Step != step {
+			step = j.Progress.Step
+			logrus.Infof("%s: %s", file, step)
+		}
+
+		timer := time.NewTimer(jobPollInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return j, ctx.Err()
+		}
+
+		next, err := client.getJob(ctx, j.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return j, ctx.Err()
+			}
+			logrus.Warnf("查询任务 %s 失败，稍后重试: %v", j.ID, err)
+			continue
+		}
+		j = next
+	}
+	return j, nil
+}
+
+// pace 两篇笔记之间的等待时间
+func pace(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(int64(jitter)))
+}
+
+// wait 等待 d，ctx 取消时提前返回
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	logrus.Infof("等待 %s 后发布下一篇", d.Round(time.Second))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
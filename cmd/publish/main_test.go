@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer 模拟服务的发布任务接口：提交后任务先执行中，第二次查询时结束
+type fakeServer struct {
+	mu          sync.Mutex
+	keys        []string
+	polls       int
+	status      string // 任务最终状态
+	jobError    string
+	unconfirmed bool // 任务失败时笔记可能已经发布
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	respond := func(data any) {
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "data": data})
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/publish":
+		var req publishRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Async {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.keys = append(f.keys, req.IdempotencyKey)
+		status := "queued"
+		if req.ScheduleMode == scheduleModeLocal {
+			status = jobScheduled
+		}
+		respond(map[string]any{"job_id": "job_1", "status": status})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/jobs/job_1":
+		f.polls++
+		if f.polls < 2 {
+			respond(map[string]any{"job_id": "job_1", "status": "running", "progress": map[string]any{"step": "uploading"}})
+			return
+		}
+		respond(map[string]any{
+			"job_id":      "job_1",
+			"status":      f.status,
+			"error":       f.jobError,
+			"unconfirmed": f.unconfirmed,
+			"result":      map[string]any{"post_id": "p1", "post_url": "https://www.xiaohongshu.com/explore/p1"},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestPublishNote(t *testing.T) {
+	jobPollInterval = 10 * time.Millisecond
+	noSave := func() error { return nil }
+
+	t.Run("waits for the job", func(t *testing.T) {
+		srv := httptest.NewServer(&fakeServer{status: jobSucceeded})
+		defer srv.Close()
+
+		n := &note{File: "01.yaml", Fingerprint: "fp", Manifest: Manifest{Title: "标题"}}
+		r := &noteResult{File: n.File}
+		if err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second); err != nil {
+			t.Fatalf("publishNote() unexpected error: %v", err)
+		}
+		if r.Status != statusPublished || r.PostID != "p1" || r.JobID != "job_1" {
+			t.Fatalf("result = %+v", r)
+		}
+	})
+
+	t.Run("failed job uses a new key next time", func(t *testing.T) {
+		fake := &fakeServer{status: jobFailed, jobError: "发布失败"}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		n := &note{File: "01.yaml", Fingerprint: "fp", Manifest: Manifest{Title: "标题"}}
+		r := &noteResult{File: n.File}
+		err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second)
+		if err == nil || !strings.Contains(err.Error(), "发布失败") {
+			t.Fatalf("publishNote() error = %v", err)
+		}
+		if r.Status != statusFailed || r.Retries != 1 {
+			t.Fatalf("result = %+v", r)
+		}
+
+		fake.polls = 0
+		fake.status = jobSucceeded
+		if err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second); err != nil {
+			t.Fatalf("publishNote() retry unexpected error: %v", err)
+		}
+		if got := strings.Join(fake.keys, ","); got != "cmd-publish-fp,cmd-publish-fp-1" {
+			t.Fatalf("idempotency keys = %s", got)
+		}
+	})
+
+	t.Run("unconfirmed job is not resubmitted on resume", func(t *testing.T) {
+		fake := &fakeServer{status: jobFailed, jobError: "等待发布结果超时", unconfirmed: true}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		n := &note{File: "01.yaml", Fingerprint: "fp", Manifest: Manifest{Title: "标题"}}
+		r := &noteResult{File: n.File}
+		if err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second); err == nil {
+			t.Fatalf("publishNote() expected error, got nil")
+		}
+		if r.Status != statusUnconfirmed || r.Retries != 0 {
+			t.Fatalf("result = %+v, want unconfirmed with the old key", r)
+		}
+
+		// -resume 不提交可能已经发布的笔记
+		notes := []*note{n}
+		res := newResults("", "", notes, &results{Notes: []*noteResult{r}})
+		if todo, err := pendingNotes(notes, res, false); err == nil || len(todo) != 0 {
+			t.Fatalf("pendingNotes() = %d notes, %v; want error", len(todo), err)
+		}
+		if len(fake.keys) != 1 {
+			t.Fatalf("unconfirmed note was resubmitted: %v", fake.keys)
+		}
+
+		// 确认没有发布后加 -retry-unconfirmed，换一个 key 重新提交
+		todo, err := pendingNotes(notes, res, true)
+		if err != nil || len(todo) != 1 {
+			t.Fatalf("pendingNotes() with retry = %d notes, %v", len(todo), err)
+		}
+		fake.polls = 0
+		fake.status, fake.unconfirmed = jobSucceeded, false
+		if err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second); err != nil {
+			t.Fatalf("publishNote() retry unexpected error: %v", err)
+		}
+		if got := strings.Join(fake.keys, ","); got != "cmd-publish-fp,cmd-publish-fp-1" {
+			t.Fatalf("idempotency keys = %s", got)
+		}
+	})
+
+	t.Run("local schedule", func(t *testing.T) {
+		srv := httptest.NewServer(&fakeServer{})
+		defer srv.Close()
+
+		n := &note{File: "01.yaml", Fingerprint: "fp", Manifest: Manifest{
+			Title:        "标题",
+			ScheduleAt:   time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339),
+			ScheduleMode: scheduleModeLocal,
+		}}
+		r := &noteResult{File: n.File}
+		if err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second); err != nil {
+			t.Fatalf("publishNote() unexpected error: %v", err)
+		}
+		if r.Status != statusScheduled || !r.done() {
+			t.Fatalf("result = %+v", r)
+		}
+	})
+
+	t.Run("schedule out of platform window is not submitted", func(t *testing.T) {
+		fake := &fakeServer{}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		n := &note{File: "01.yaml", Fingerprint: "fp", Manifest: Manifest{
+			Title:      "标题",
+			ScheduleAt: time.Now().Add(30 * time.Minute).Format(time.RFC3339),
+		}}
+		r := &noteResult{File: n.File}
+		err := publishNote(t.Context(), newAPIClient(srv.URL), n, r, noSave, time.Second)
+		if err == nil || !strings.Contains(err.Error(), "schedule_mode: local") {
+			t.Fatalf("publishNote() error = %v", err)
+		}
+		if len(fake.keys) != 0 {
+			t.Fatalf("note out of the platform window should not be submitted")
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mediastore"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+	"gopkg.in/yaml.v3"
+)
+
+// 平台定时发布支持的时间范围，与服务端一致
+const (
+	minScheduleAhead = time.Hour
+	maxScheduleAhead = 14 * 24 * time.Hour
+)
+
+// 定时发布方式，与服务的 schedule_mode 一致
+const (
+	scheduleModePlatform = "platform" // 平台定时发布，只支持 1 小时至 14 天内
+	scheduleModeLocal    = "local"    // 服务保存任务，到时间再发布，时间不受限制
+)
+
+// Manifest 一篇笔记的清单文件（YAML 或 JSON）
+type Manifest struct {
+	Title         string   `yaml:"title" json:"title"`
+	Body          string   `yaml:"body" json:"body"`
+	Tags          []string `yaml:"tags" json:"tags"`
+	Images        []string `yaml:"images" json:"images"`                           // 本地路径（相对路径按清单文件所在目录解析）、图片链接或 media_id
+	ScheduleAt    string   `yaml:"schedule_at" json:"schedule_at"`                 // 定时发布时间，RFC3339 格式，为空则立即发布
+	ScheduleMode  string   `yaml:"schedule_mode" json:"schedule_mode,omitempty"`   // 定时发布方式：platform（默认）| local
+	Visibility    string   `yaml:"visibility" json:"visibility"`                   // 公开（默认）/仅自己可见/仅互关好友可见
+	ContentFormat string   `yaml:"content_format" json:"content_format,omitempty"` // body 的格式：plain（默认）| markdown
+}
+
+// note 从清单文件读取的一篇笔记
+type note struct {
+	File        string // 清单文件名，作为结果文件中的标识
+	Path        string
+	Manifest    Manifest
+	Fingerprint string // 清单内容摘要，用于发现已发布的清单被修改，以及生成 idempotency_key
+	Problems    []string
+}
+
+// isManifestFile 是否为清单文件
+func isManifestFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return !strings.HasPrefix(name, ".")
+	}
+	return false
+}
+
+// loadManifests 按文件名顺序读取目录下的清单文件，跳过结果文件
+func loadManifests(dir, resultsPath string) ([]*note, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取清单目录失败: %w", err)
+	}
+	resultsAbs, _ := filepath.Abs(resultsPath)
+
+	var notes []*note
+	for _, entry := range entries {
+		if entry.IsDir() || !isManifestFile(entry.Name()) {
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if path == resultsAbs {
+			continue
+		}
+
+		n := &note{File: entry.Name(), Path: path}
+		if err := readManifest(path, &n.Manifest); err != nil {
+			n.Problems = append(n.Problems, err.Error())
+		}
+		n.Fingerprint = fingerprint(n.File, n.Manifest)
+		notes = append(notes, n)
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("清单目录中没有 .yaml、.yml 或 .json 文件: %s", dir)
+	}
+
+	sort.Slice(notes, func(i, j int) bool { return notes[i].File < notes[j].File })
+	return notes, nil
+}
+
+// readManifest 读取一个清单文件，不允许未知字段，避免字段名写错后被静默忽略
+func readManifest(path string, m *Manifest) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(m); err != nil {
+			return fmt.Errorf("解析 JSON 失败: %w", err)
+		}
+		return nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("清单文件为空")
+		}
+		return fmt.Errorf("解析 YAML 失败: %w", err)
+	}
+	return nil
+}
+
+// validate 发布前检查清单：编辑器规则、图片、定时发布时间和可见范围，问题记录到 Problems。
+// 本地图片的相对路径会改为绝对路径，服务需要能读取到这些文件
+func (n *note) validate(now time.Time) {
+	if len(n.Problems) > 0 {
+		return
+	}
+	m := &n.Manifest
+
+	if _, err := xhsutil.ParseContentFormat(m.ContentFormat); err != nil {
+		n.Problems = append(n.Problems, err.Error())
+		return
+	}
+	content, tags := n.text()
+	report := xhsutil.ValidateNote(xhsutil.Note{
+		Title:      m.Title,
+		Content:    content,
+		Tags:       tags,
+		ImageCount: len(m.Images),
+	})
+	n.Problems = append(n.Problems, report.Errors()...)
+
+	for i, image := range m.Images {
+		resolved, err := resolveImage(filepath.Dir(n.Path), image)
+		if err != nil {
+			n.Problems = append(n.Problems, fmt.Sprintf("第%d张图片: %v", i+1, err))
+			continue
+		}
+		m.Images[i] = resolved
+	}
+
+	if err := n.checkSchedule(now); err != nil {
+		n.Problems = append(n.Problems, err.Error())
+	}
+	if _, err := xiaohongshu.ParseVisibility(m.Visibility); err != nil {
+		n.Problems = append(n.Problems, err.Error())
+	}
+}
+
+// text 实际发布的正文和标签，content_format 为 markdown 时与服务一样先转换
+func (n *note) text() (string, []string) {
+	m := n.Manifest
+	if m.ContentFormat != xhsutil.ContentFormatMarkdown {
+		return m.Body, m.Tags
+	}
+	content, tags := xhsutil.MarkdownToText(m.Body)
+	return content, xhsutil.MergeTags(m.Tags, tags)
+}
+
+// resolveImage 检查本地图片是否存在并转为绝对路径，链接、base64 图片和 media_id 原样返回
+func resolveImage(baseDir, image string) (string, error) {
+	image = strings.TrimSpace(image)
+	switch {
+	case image == "":
+		return "", fmt.Errorf("图片为空")
+	case downloader.IsImageURL(image), downloader.IsInlineImage(image), mediastore.IsMediaID(image):
+		return image, nil
+	case downloader.IsFileURL(image):
+		path, err := downloader.FileURLToPath(image)
+		if err != nil {
+			return "", err
+		}
+		image = path
+	}
+
+	if !filepath.IsAbs(image) {
+		image = filepath.Join(baseDir, image)
+	}
+	info, err := os.Stat(image)
+	if err != nil {
+		return "", fmt.Errorf("图片不存在: %s", image)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("不是图片文件: %s", image)
+	}
+	return image, nil
+}
+
+// checkSchedule 检查定时发布设置。批量发布时前面的笔记和间隔会占用时间，
+// 平台定时发布的时间可能在等待期间超出范围，所以发布每篇之前会用当时的时间再检查一次
+func (n *note) checkSchedule(now time.Time) error {
+	m := n.Manifest
+	switch m.ScheduleMode {
+	case "", scheduleModePlatform:
+		if m.ScheduleAt == "" {
+			return nil
+		}
+		return checkScheduleAt(m.ScheduleAt, now)
+	case scheduleModeLocal:
+		if m.ScheduleAt == "" {
+			return fmt.Errorf("schedule_mode 为 local 时必须设置 schedule_at")
+		}
+		t, err := parseScheduleAt(m.ScheduleAt)
+		if err != nil {
+			return err
+		}
+		if !t.After(now) {
+			return fmt.Errorf("schedule_at 必须晚于当前时间: %s", m.ScheduleAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("不支持的 schedule_mode: %s（支持 platform、local）", m.ScheduleMode)
+}
+
+// parseScheduleAt 解析 RFC3339 格式的定时发布时间
+func parseScheduleAt(scheduleAt string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, scheduleAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("schedule_at 格式错误，应为 RFC3339 格式如 2025-01-20T10:30:00+08:00: %s", scheduleAt)
+	}
+	return t, nil
+}
+
+// checkScheduleAt 检查定时发布时间是否在平台支持的 1 小时至 14 天内
+func checkScheduleAt(scheduleAt string, now time.Time) error {
+	t, err := parseScheduleAt(scheduleAt)
+	if err != nil {
+		return err
+	}
+	if t.Before(now.Add(minScheduleAhead)) {
+		return fmt.Errorf("schedule_at 必须至少在1小时后: %s", scheduleAt)
+	}
+	if t.After(now.Add(maxScheduleAhead)) {
+		return fmt.Errorf("schedule_at 不能超过14天: %s", scheduleAt)
+	}
+	return nil
+}
+
+// fingerprint 计算清单内容的摘要，在解析图片路径之前计算，清单不变时摘要不变
+func fingerprint(file string, m Manifest) string {
+	data, _ := json.Marshal(struct {
+		File     string   `json:"file"`
+		Manifest Manifest `json:"manifest"`
+	}{file, m})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// idempotencyKey 清单内容不变时 key 不变，-resume 时重新提交会拿到上次提交的任务，不会重复发布。
+// 上次的任务失败后 retries 加一，换一个 key 才能重新提交
+func (n *note) idempotencyKey(retries int) string {
+	if retries == 0 {
+		return "cmd-publish-" + n.Fingerprint
+	}
+	return fmt.Sprintf("cmd-publish-%s-%d", n.Fingerprint, retries)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "02.json"), `{"title": "第二篇", "body": "正文", "images": ["a.jpg"]}`)
+	writeFile(t, filepath.Join(dir, "01.yaml"), "title: 第一篇\nbody: |\n  正文\ntags: [旅行]\nimages:\n  - a.jpg\n")
+	writeFile(t, filepath.Join(dir, "03.yml"), "title: 第三篇\ncontent: 字段名写错\n")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "不是清单")
+	writeFile(t, filepath.Join(dir, defaultResultsFile), `{"notes": []}`)
+
+	notes, err := loadManifests(dir, filepath.Join(dir, defaultResultsFile))
+	if err != nil {
+		t.Fatalf("loadManifests() unexpected error: %v", err)
+	}
+
+	var files []string
+	for _, n := range notes {
+		files = append(files, n.File)
+	}
+	if got := strings.Join(files, ","); got != "01.yaml,02.json,03.yml" {
+		t.Fatalf("loadManifests() files = %s", got)
+	}
+	if notes[0].Manifest.Title != "第一篇" || notes[0].Manifest.Body != "正文\n" || len(notes[0].Manifest.Tags) != 1 {
+		t.Fatalf("01.yaml parsed as %+v", notes[0].Manifest)
+	}
+	if len(notes[1].Problems) != 0 {
+		t.Fatalf("02.json unexpected problems: %v", notes[1].Problems)
+	}
+	if len(notes[2].Problems) == 0 {
+		t.Fatalf("03.yml with unknown field expected a problem")
+	}
+}
+
+func TestNoteValidate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.jpg"), "jpg")
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		manifest Manifest
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg", "https://example.com/b.jpg"}},
+		},
+		{
+			name:     "missing image",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"missing.jpg"}},
+			wantErr:  "图片不存在",
+		},
+		{
+			name:     "schedule too soon",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, ScheduleAt: now.Add(10 * time.Minute).Format(time.RFC3339)},
+			wantErr:  "至少在1小时后",
+		},
+		{
+			name:     "local schedule beyond platform window",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, ScheduleAt: now.Add(60 * 24 * time.Hour).Format(time.RFC3339), ScheduleMode: "local"},
+		},
+		{
+			name:     "local schedule without time",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, ScheduleMode: "local"},
+			wantErr:  "必须设置 schedule_at",
+		},
+		{
+			name:     "bad schedule mode",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, ScheduleAt: now.Add(2 * time.Hour).Format(time.RFC3339), ScheduleMode: "server"},
+			wantErr:  "schedule_mode",
+		},
+		{
+			name:     "bad visibility",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, Visibility: "所有人"},
+			wantErr:  "不支持的可见范围",
+		},
+		{
+			name:     "bad content format",
+			manifest: Manifest{Title: "标题", Body: "正文", Images: []string{"a.jpg"}, ContentFormat: "html"},
+			wantErr:  "content_format",
+		},
+		{
+			name:     "no images",
+			manifest: Manifest{Title: "标题", Body: "正文"},
+			wantErr:  "图片",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &note{File: "note.yaml", Path: filepath.Join(dir, "note.yaml"), Manifest: tt.manifest}
+			n.validate(now)
+
+			problems := strings.Join(n.Problems, "；")
+			if tt.wantErr == "" {
+				if problems != "" {
+					t.Fatalf("validate() unexpected problems: %s", problems)
+				}
+				if n.Manifest.Images[0] != filepath.Join(dir, "a.jpg") {
+					t.Fatalf("relative image resolved to %s", n.Manifest.Images[0])
+				}
+				return
+			}
+			if !strings.Contains(problems, tt.wantErr) {
+				t.Fatalf("validate() problems = %q, want %q", problems, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckScheduleBeforeEachNote(t *testing.T) {
+	start := time.Now()
+	n := &note{Manifest: Manifest{ScheduleAt: start.Add(90 * time.Minute).Format(time.RFC3339)}}
+
+	if err := n.checkSchedule(start); err != nil {
+		t.Fatalf("checkSchedule() at start unexpected error: %v", err)
+	}
+	// 前面的笔记发布了 40 分钟后，离发布时间已不到 1 小时
+	if err := n.checkSchedule(start.Add(40 * time.Minute)); err == nil {
+		t.Fatalf("checkSchedule() after 40m expected error, got nil")
+	}
+
+	n.Manifest.ScheduleMode = scheduleModeLocal
+	if err := n.checkSchedule(start.Add(40 * time.Minute)); err != nil {
+		t.Fatalf("checkSchedule() local unexpected error: %v", err)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	n := &note{Fingerprint: "abc"}
+	if got := n.idempotencyKey(0); got != "cmd-publish-abc" {
+		t.Fatalf("idempotencyKey(0) = %s", got)
+	}
+	if got := n.idempotencyKey(2); got != "cmd-publish-abc-2" {
+		t.Fatalf("idempotencyKey(2) = %s", got)
+	}
+}
+
+func TestNewResultsResume(t *testing.T) {
+	notes := []*note{
+		{File: "01.yaml", Manifest: Manifest{Title: "第一篇"}},
+		{File: "02.yaml", Manifest: Manifest{Title: "第二篇"}},
+	}
+	previous := &results{Notes: []*noteResult{
+		{File: "01.yaml", Status: statusPublished, PostID: "p1"},
+		{File: "02.yaml", Status: statusFailed, Error: "超时"},
+		{File: "00.yaml", Status: statusPublished, PostID: "p0"},
+		{File: "08.yaml", Status: statusScheduled, JobID: "job_1"},
+		{File: "09.yaml", Status: statusFailed},
+	}}
+
+	res := newResults("results.json", ".", notes, previous)
+
+	if got := res.get("01.yaml"); got.Status != statusPublished || got.PostID != "p1" {
+		t.Fatalf("published note not kept: %+v", got)
+	}
+	if got := res.get("02.yaml"); got.Status != statusFailed {
+		t.Fatalf("failed note status = %s", got.Status)
+	}
+	if res.get("00.yaml") == nil {
+		t.Fatalf("published note whose manifest was removed should be kept")
+	}
+	if res.get("08.yaml") == nil {
+		t.Fatalf("scheduled note whose manifest was removed should be kept")
+	}
+	if res.get("09.yaml") != nil {
+		t.Fatalf("failed note whose manifest was removed should be dropped")
+	}
+
+	fresh := newResults("results.json", ".", notes, nil)
+	if fresh.count(statusPending) != 2 {
+		t.Fatalf("fresh results pending = %d, want 2", fresh.count(statusPending))
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// 每篇笔记的发布状态
+const (
+	statusPending     = "pending"     // 还没有发布
+	statusPublished   = "published"   // 已发布或已设置平台定时发布
+	statusScheduled   = "scheduled"   // schedule_mode 为 local，服务到 schedule_at 时发布
+	statusFailed      = "failed"      // 发布失败，-resume 时重试
+	statusUnconfirmed = "unconfirmed" // 任务没有成功，但笔记可能已经发布；-resume 时不重新提交，需要先确认
+)
+
+// noteResult 一篇笔记的发布结果
+type noteResult struct {
+	File          string     `json:"file"`
+	Title         string     `json:"title"`
+	Status        string     `json:"status"`
+	Fingerprint   string     `json:"fingerprint"` // 清单内容摘要，已发布的清单被修改时提示
+	ScheduleAt    string     `json:"schedule_at,omitempty"`
+	JobID         string     `json:"job_id,omitempty"` // 服务中的发布任务
+	PostID        string     `json:"post_id,omitempty"`
+	PostURL       string     `json:"post_url,omitempty"`
+	NoteIDUnknown bool       `json:"note_id_unknown,omitempty"` // 已发布，但服务没能获取笔记 ID，需要到创作者中心确认
+	Error         string     `json:"error,omitempty"`
+	Attempts      int        `json:"attempts,omitempty"`
+	Retries       int        `json:"retries,omitempty"` // 任务失败后重新提交的次数，用于生成新的 idempotency_key
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// done 已发布或已交给服务定时发布，-resume 时跳过
+func (r *noteResult) done() bool {
+	return r.Status == statusPublished || r.Status == statusScheduled
+}
+
+// results 结果文件，每发布一篇笔记写入一次
+type results struct {
+	Dir       string        `json:"dir"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Notes     []*noteResult `json:"notes"`
+
+	path string
+}
+
+// loadResults 读取上次的结果文件，文件不存在时返回 nil
+func loadResults(path string) (*results, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取结果文件失败: %w", err)
+	}
+
+	var r results
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("解析结果文件失败: %w", err)
+	}
+	r.path = path
+	return &r, nil
+}
+
+// newResults 按清单顺序生成本次的结果，保留上次已发布的记录。
+// 上次的结果中有、清单目录中已经没有的已发布记录追加到末尾，不会丢失
+func newResults(path, dir string, notes []*note, previous *results) *results {
+	prev := make(map[string]*noteResult)
+	if previous != nil {
+		for _, r := range previous.Notes {
+			prev[r.File] = r
+		}
+	}
+
+	r := &results{Dir: dir, path: path}
+	seen := make(map[string]bool)
+	for _, n := range notes {
+		seen[n.File] = true
+		if p, ok := prev[n.File]; ok {
+			r.Notes = append(r.Notes, p)
+			continue
+		}
+		r.Notes = append(r.Notes, &noteResult{
+			File:   n.File,
+			Title:  n.Manifest.Title,
+			Status: statusPending,
+		})
+	}
+	if previous != nil {
+		for _, p := range previous.Notes {
+			if !seen[p.File] && p.done() {
+				r.Notes = append(r.Notes, p)
+			}
+		}
+	}
+	return r
+}
+
+// get 返回清单文件对应的结果
+func (r *results) get(file string) *noteResult {
+	for _, n := range r.Notes {
+		if n.File == file {
+			return n
+		}
+	}
+	return nil
+}
+
+// count 统计各状态的笔记数
+func (r *results) count(status string) int {
+	total := 0
+	for _, n := range r.Notes {
+		if n.Status == status {
+			total++
+		}
+	}
+	return total
+}
+
+// save 写入结果文件，先写临时文件再重命名，中途退出不会留下不完整的文件
+func (r *results) save() error {
+	r.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	return nil
+}
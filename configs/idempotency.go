@@ -0,0 +1,30 @@
+package configs
+
+import (
+	"path/filepath"
+	"time"
+)
+
+const (
+	IdempotencyDir = "idempotency"
+
+	// DefaultIdempotencyTTL idempotency_key 默认保留时长
+	DefaultIdempotencyTTL = 24 * time.Hour
+)
+
+var idempotencyTTL = DefaultIdempotencyTTL
+
+// GetIdempotencyPath idempotency_key 记录的保存目录，放在任务目录下，随 -jobs-dir 一起保存
+func GetIdempotencyPath() string {
+	return filepath.Join(GetJobsPath(), IdempotencyDir)
+}
+
+// SetIdempotencyTTL 设置 idempotency_key 的保留时长
+func SetIdempotencyTTL(ttl time.Duration) {
+	idempotencyTTL = ttl
+}
+
+// GetIdempotencyTTL idempotency_key 的保留时长
+func GetIdempotencyTTL() time.Duration {
+	return idempotencyTTL
+}
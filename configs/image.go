@@ -7,8 +7,39 @@ import (
 
 const (
 	ImagesDir = "xiaohongshu_images"
+
+	// DefaultImageMaxEdge 上传前图片最长边的默认上限（像素）
+	DefaultImageMaxEdge = 4096
+	// DefaultImageMaxMB 上传前图片文件大小的默认上限（MB）
+	DefaultImageMaxMB = 10
+)
+
+var (
+	imageMaxEdge = DefaultImageMaxEdge
+	imageMaxMB   = DefaultImageMaxMB
 )
 
 func GetImagesPath() string {
 	return filepath.Join(os.TempDir(), ImagesDir)
 }
+
+// GetPreparedImagesPath 预处理后图片的保存目录
+func GetPreparedImagesPath() string {
+	return filepath.Join(GetImagesPath(), "prepared")
+}
+
+// SetImageLimits 设置上传前图片预处理的最长边和文件大小上限，0 表示不限制
+func SetImageLimits(maxEdge, maxMB int) {
+	imageMaxEdge = maxEdge
+	imageMaxMB = maxMB
+}
+
+// GetImageMaxEdge 图片最长边上限（像素）
+func GetImageMaxEdge() int {
+	return imageMaxEdge
+}
+
+// GetImageMaxBytes 图片文件大小上限（字节）
+func GetImageMaxBytes() int64 {
+	return int64(imageMaxMB) * 1024 * 1024
+}
@@ -0,0 +1,23 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const JobsDir = "xiaohongshu_jobs"
+
+var jobsPath string
+
+// SetJobsPath 设置后台任务的保存目录，为空时使用系统临时目录
+func SetJobsPath(path string) {
+	jobsPath = path
+}
+
+// GetJobsPath 后台任务的保存目录
+func GetJobsPath() string {
+	if jobsPath != "" {
+		return jobsPath
+	}
+	return filepath.Join(os.TempDir(), JobsDir)
+}
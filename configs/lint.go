@@ -0,0 +1,42 @@
+package configs
+
+import "fmt"
+
+// 敏感词检查模式
+const (
+	LintModeBlock = "block" // 命中 high 级别的词时阻止发布
+	LintModeWarn  = "warn"  // 只在结果中提示
+	LintModeOff   = "off"   // 不检查
+
+	DefaultLintMode = LintModeWarn
+)
+
+var (
+	lintWordFiles []string
+	lintMode      = DefaultLintMode
+)
+
+// SetLintWordFiles 设置敏感词词库文件或目录，为空时使用内置词库
+func SetLintWordFiles(paths []string) {
+	lintWordFiles = paths
+}
+
+// GetLintWordFiles 敏感词词库文件或目录
+func GetLintWordFiles() []string {
+	return lintWordFiles
+}
+
+// SetLintMode 设置敏感词检查模式
+func SetLintMode(mode string) error {
+	switch mode {
+	case LintModeBlock, LintModeWarn, LintModeOff:
+		lintMode = mode
+		return nil
+	}
+	return fmt.Errorf("无效的敏感词检查模式: %s，可选 block、warn、off", mode)
+}
+
+// GetLintMode 敏感词检查模式
+func GetLintMode() string {
+	return lintMode
+}
@@ -0,0 +1,31 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	MediaDir = "xiaohongshu_media"
+
+	// DefaultMediaTTL 通过 /api/v1/media 上传的文件默认保留时长
+	DefaultMediaTTL = 24 * time.Hour
+)
+
+var mediaTTL = DefaultMediaTTL
+
+// GetMediaPath 上传文件的保存目录
+func GetMediaPath() string {
+	return filepath.Join(os.TempDir(), MediaDir)
+}
+
+// SetMediaTTL 设置上传文件的保留时长
+func SetMediaTTL(ttl time.Duration) {
+	mediaTTL = ttl
+}
+
+// GetMediaTTL 上传文件的保留时长
+func GetMediaTTL() time.Duration {
+	return mediaTTL
+}
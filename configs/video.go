@@ -0,0 +1,23 @@
+package configs
+
+import "path/filepath"
+
+// DefaultVideoDownloadMaxMB 从链接下载视频的默认大小上限（MB）
+const DefaultVideoDownloadMaxMB = 2048
+
+var videoDownloadMaxMB = DefaultVideoDownloadMaxMB
+
+// GetVideosPath 从链接下载的视频的保存目录
+func GetVideosPath() string {
+	return filepath.Join(GetImagesPath(), "videos")
+}
+
+// SetVideoDownloadMaxMB 设置从链接下载视频的大小上限，0 表示不限制
+func SetVideoDownloadMaxMB(maxMB int) {
+	videoDownloadMaxMB = maxMB
+}
+
+// GetVideoDownloadMaxBytes 从链接下载视频的大小上限（字节）
+func GetVideoDownloadMaxBytes() int64 {
+	return int64(videoDownloadMaxMB) * 1024 * 1024
+}
@@ -0,0 +1,395 @@
+package cookies
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// Format cookies 的文本格式
+type Format string
+
+const (
+	FormatAuto     Format = "auto"     // 自动识别
+	FormatNetscape Format = "netscape" // Netscape cookies.txt
+	FormatJSON     Format = "json"     // EditThisCookie 风格的 JSON 数组
+	FormatHeader   Format = "header"   // 原始 Cookie 请求头，如 "a=1; b=2"
+)
+
+// xhsRootDomain 只导入/导出小红书域名下的 cookies
+const xhsRootDomain = "xiaohongshu.com"
+
+// ParseFormat 解析格式名称，空字符串视为自动识别
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatNetscape, "cookies.txt", "txt":
+		return FormatNetscape, nil
+	case FormatJSON, "editthiscookie":
+		return FormatJSON, nil
+	case FormatHeader, "cookie":
+		return FormatHeader, nil
+	}
+	return "", errors.Errorf("不支持的 cookies 格式: %s（支持 netscape/json/header）", s)
+}
+
+// DetectFormat 根据内容猜测 cookies 格式
+func DetectFormat(data string) Format {
+	trimmed := strings.TrimSpace(data)
+	switch {
+	case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+		return FormatJSON
+	case strings.HasPrefix(trimmed, "# Netscape") || strings.HasPrefix(trimmed, "# HTTP Cookie File") ||
+		strings.Contains(trimmed, "\t"):
+		return FormatNetscape
+	default:
+		return FormatHeader
+	}
+}
+
+// ParseCookies 将指定格式的 cookies 文本解析为浏览器可直接加载的格式，
+// 并过滤掉非小红书域名的 cookies。
+func ParseCookies(data string, format Format) ([]*proto.NetworkCookie, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, errors.New("cookies 内容为空")
+	}
+
+	if format == FormatAuto || format == "" {
+		format = DetectFormat(data)
+	}
+
+	var (
+		parsed []*proto.NetworkCookie
+		err    error
+	)
+	switch format {
+	case FormatNetscape:
+		parsed, err = parseNetscape(data)
+	case FormatJSON:
+		parsed, err = parseJSON(data)
+	case FormatHeader:
+		parsed, err = parseHeader(data)
+	default:
+		return nil, errors.Errorf("不支持的 cookies 格式: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := FilterXhsCookies(parsed)
+	if len(result) == 0 {
+		return nil, errors.New("没有找到小红书域名（xiaohongshu.com）下的 cookies")
+	}
+
+	return result, nil
+}
+
+// ExportCookies 将浏览器保存的 cookies 转换为指定格式的文本
+func ExportCookies(cks []*proto.NetworkCookie, format Format) (string, error) {
+	cks = FilterXhsCookies(cks)
+
+	switch format {
+	case FormatNetscape:
+		return formatNetscape(cks), nil
+	case FormatJSON, FormatAuto, "":
+		return formatJSON(cks)
+	case FormatHeader:
+		return formatHeader(cks), nil
+	}
+	return "", errors.Errorf("不支持的 cookies 格式: %s", format)
+}
+
+func parseNetscape(data string) ([]*proto.NetworkCookie, error) {
+	var result []*proto.NetworkCookie
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			httpOnly = true
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, errors.Errorf("cookies.txt 第%d行格式错误，需要7个以制表符分隔的字段", lineNo)
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, errors.Errorf("cookies.txt 第%d行过期时间无效: %s", lineNo, fields[4])
+		}
+
+		ck := &proto.NetworkCookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    strings.Join(fields[6:], "\t"),
+			HTTPOnly: httpOnly,
+		}
+		setExpires(ck, expires)
+		result = append(result, ck)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "读取 cookies.txt 失败")
+	}
+
+	return result, nil
+}
+
+// jsonCookie 同时兼容 EditThisCookie 导出格式和 Chrome DevTools 协议格式
+type jsonCookie struct {
+	Name           string   `json:"name"`
+	Value          string   `json:"value"`
+	Domain         string   `json:"domain"`
+	Path           string   `json:"path"`
+	Secure         bool     `json:"secure"`
+	HTTPOnly       bool     `json:"httpOnly"`
+	Session        bool     `json:"session"`
+	SameSite       string   `json:"sameSite"`
+	ExpirationDate *float64 `json:"expirationDate,omitempty"`
+	Expires        *float64 `json:"expires,omitempty"`
+	HostOnly       bool     `json:"hostOnly"`
+	StoreID        string   `json:"storeId,omitempty"`
+}
+
+func parseJSON(data string) ([]*proto.NetworkCookie, error) {
+	trimmed := strings.TrimSpace(data)
+
+	var items []jsonCookie
+	if strings.HasPrefix(trimmed, "{") {
+		// 兼容 {"cookies": [...]} 的包装格式
+		var wrapper struct {
+			Cookies []jsonCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &wrapper); err != nil {
+			return nil, errors.Wrap(err, "解析 cookies JSON 失败")
+		}
+		items = wrapper.Cookies
+	} else if err := json.Unmarshal([]byte(trimmed), &items); err != nil {
+		return nil, errors.Wrap(err, "解析 cookies JSON 失败")
+	}
+
+	result := make([]*proto.NetworkCookie, 0, len(items))
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+
+		ck := &proto.NetworkCookie{
+			Name:     item.Name,
+			Value:    item.Value,
+			Domain:   item.Domain,
+			Path:     item.Path,
+			Secure:   item.Secure,
+			HTTPOnly: item.HTTPOnly,
+			SameSite: parseSameSite(item.SameSite),
+		}
+		if ck.Path == "" {
+			ck.Path = "/"
+		}
+
+		switch {
+		case item.Session:
+			setExpires(ck, 0)
+		case item.ExpirationDate != nil:
+			setExpires(ck, *item.ExpirationDate)
+		case item.Expires != nil:
+			setExpires(ck, *item.Expires)
+		default:
+			setExpires(ck, 0)
+		}
+		result = append(result, ck)
+	}
+
+	return result, nil
+}
+
+func parseHeader(data string) ([]*proto.NetworkCookie, error) {
+	header := strings.TrimSpace(data)
+	if idx := strings.Index(header, ":"); idx >= 0 && strings.EqualFold(strings.TrimSpace(header[:idx]), "cookie") {
+		header = header[idx+1:]
+	}
+
+	var result []*proto.NetworkCookie
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, errors.Errorf("Cookie 头格式错误: %s", part)
+		}
+
+		ck := &proto.NetworkCookie{
+			Name:   strings.TrimSpace(name),
+			Value:  strings.TrimSpace(value),
+			Domain: "." + xhsRootDomain,
+			Path:   "/",
+		}
+		setExpires(ck, 0)
+		result = append(result, ck)
+	}
+
+	return result, nil
+}
+
+func formatNetscape(cks []*proto.NetworkCookie) string {
+	var sb strings.Builder
+	sb.WriteString("# Netscape HTTP Cookie File\n")
+
+	for _, ck := range cks {
+		domain := ck.Domain
+		if ck.HTTPOnly {
+			domain = "#HttpOnly_" + domain
+		}
+
+		expires := int64(0)
+		if !ck.Session && ck.Expires > 0 {
+			expires = int64(ck.Expires)
+		}
+
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain,
+			boolToNetscape(strings.HasPrefix(ck.Domain, ".")),
+			defaultPath(ck.Path),
+			boolToNetscape(ck.Secure),
+			expires,
+			ck.Name,
+			ck.Value,
+		)
+	}
+
+	return sb.String()
+}
+
+func formatJSON(cks []*proto.NetworkCookie) (string, error) {
+	items := make([]jsonCookie, 0, len(cks))
+	for _, ck := range cks {
+		item := jsonCookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   ck.Domain,
+			Path:     defaultPath(ck.Path),
+			Secure:   ck.Secure,
+			HTTPOnly: ck.HTTPOnly,
+			Session:  ck.Session || ck.Expires <= 0,
+			SameSite: formatSameSite(ck.SameSite),
+			HostOnly: !strings.HasPrefix(ck.Domain, "."),
+			StoreID:  "0",
+		}
+		if !item.Session {
+			expires := float64(ck.Expires)
+			item.ExpirationDate = &expires
+		}
+		items = append(items, item)
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "序列化 cookies 失败")
+	}
+	return string(data), nil
+}
+
+func formatHeader(cks []*proto.NetworkCookie) string {
+	// 按名称排序并去重，保证输出稳定
+	seen := make(map[string]string, len(cks))
+	names := make([]string, 0, len(cks))
+	for _, ck := range cks {
+		if _, ok := seen[ck.Name]; !ok {
+			names = append(names, ck.Name)
+		}
+		seen[ck.Name] = ck.Value
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+seen[name])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FilterXhsCookies 过滤出小红书域名下的 cookies
+func FilterXhsCookies(cks []*proto.NetworkCookie) []*proto.NetworkCookie {
+	result := make([]*proto.NetworkCookie, 0, len(cks))
+	for _, ck := range cks {
+		if isXhsDomain(ck.Domain) {
+			result = append(result, ck)
+		}
+	}
+	return result
+}
+
+func isXhsDomain(domain string) bool {
+	d := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+	return d == xhsRootDomain || strings.HasSuffix(d, "."+xhsRootDomain)
+}
+
+// setExpires 设置过期时间，expires <= 0 表示会话 cookie
+func setExpires(ck *proto.NetworkCookie, expires float64) {
+	if expires <= 0 {
+		ck.Session = true
+		ck.Expires = -1
+		return
+	}
+	ck.Session = false
+	ck.Expires = proto.TimeSinceEpoch(expires)
+}
+
+func parseSameSite(s string) proto.NetworkCookieSameSite {
+	switch strings.ToLower(s) {
+	case "strict":
+		return proto.NetworkCookieSameSiteStrict
+	case "lax":
+		return proto.NetworkCookieSameSiteLax
+	case "none", "no_restriction":
+		return proto.NetworkCookieSameSiteNone
+	}
+	return ""
+}
+
+func formatSameSite(s proto.NetworkCookieSameSite) string {
+	switch s {
+	case proto.NetworkCookieSameSiteStrict:
+		return "strict"
+	case proto.NetworkCookieSameSiteLax:
+		return "lax"
+	case proto.NetworkCookieSameSiteNone:
+		return "no_restriction"
+	}
+	return "unspecified"
+}
+
+func boolToNetscape(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func defaultPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return p
+}
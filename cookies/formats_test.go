@@ -0,0 +1,109 @@
+package cookies
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Format
+	}{
+		{name: "json数组", input: `[{"name":"a","value":"1"}]`, want: FormatJSON},
+		{name: "netscape头", input: "# Netscape HTTP Cookie File\n", want: FormatNetscape},
+		{name: "制表符分隔", input: ".xiaohongshu.com\tTRUE\t/\tFALSE\t0\ta\t1", want: FormatNetscape},
+		{name: "cookie头", input: "Cookie: a=1; b=2", want: FormatHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectFormat(tt.input))
+		})
+	}
+}
+
+func TestParseCookies_Netscape(t *testing.T) {
+	data := strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		".xiaohongshu.com\tTRUE\t/\tTRUE\t1893456000\tweb_session\tabc",
+		"#HttpOnly_.xiaohongshu.com\tTRUE\t/\tFALSE\t0\ta1\txyz",
+		".example.com\tTRUE\t/\tFALSE\t0\tother\t1",
+	}, "\n")
+
+	cks, err := ParseCookies(data, FormatAuto)
+	require.NoError(t, err)
+	require.Len(t, cks, 2)
+
+	assert.Equal(t, "web_session", cks[0].Name)
+	assert.True(t, cks[0].Secure)
+	assert.False(t, cks[0].Session)
+	assert.Equal(t, proto.TimeSinceEpoch(1893456000), cks[0].Expires)
+
+	assert.Equal(t, "a1", cks[1].Name)
+	assert.True(t, cks[1].HTTPOnly)
+	assert.True(t, cks[1].Session)
+}
+
+func TestParseCookies_EditThisCookieJSON(t *testing.T) {
+	data := `[
+		{"domain":".xiaohongshu.com","expirationDate":1893456000.5,"hostOnly":false,"httpOnly":true,"name":"web_session","path":"/","sameSite":"no_restriction","secure":true,"session":false,"value":"abc"},
+		{"domain":"www.xiaohongshu.com","hostOnly":true,"name":"xsecappid","path":"/","sameSite":"lax","session":true,"value":"xhs-pc-web"},
+		{"domain":".google.com","name":"NID","value":"x"}
+	]`
+
+	cks, err := ParseCookies(data, FormatJSON)
+	require.NoError(t, err)
+	require.Len(t, cks, 2)
+
+	assert.Equal(t, proto.NetworkCookieSameSiteNone, cks[0].SameSite)
+	assert.Equal(t, proto.TimeSinceEpoch(1893456000.5), cks[0].Expires)
+	assert.Equal(t, proto.NetworkCookieSameSiteLax, cks[1].SameSite)
+	assert.True(t, cks[1].Session)
+}
+
+func TestParseCookies_Header(t *testing.T) {
+	cks, err := ParseCookies("Cookie: a1=xyz; web_session=abc", FormatAuto)
+	require.NoError(t, err)
+	require.Len(t, cks, 2)
+
+	assert.Equal(t, ".xiaohongshu.com", cks[0].Domain)
+	assert.Equal(t, "a1", cks[0].Name)
+	assert.Equal(t, "abc", cks[1].Value)
+}
+
+func TestParseCookies_NoXhsCookies(t *testing.T) {
+	_, err := ParseCookies(`[{"domain":".example.com","name":"a","value":"1"}]`, FormatJSON)
+	assert.Error(t, err)
+}
+
+func TestExportCookies_RoundTrip(t *testing.T) {
+	cks := []*proto.NetworkCookie{
+		{Name: "web_session", Value: "abc", Domain: ".xiaohongshu.com", Path: "/", Expires: 1893456000, Secure: true, HTTPOnly: true},
+		{Name: "a1", Value: "xyz", Domain: ".xiaohongshu.com", Path: "/", Expires: -1, Session: true},
+		{Name: "NID", Value: "x", Domain: ".google.com", Path: "/"},
+	}
+
+	for _, format := range []Format{FormatNetscape, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			text, err := ExportCookies(cks, format)
+			require.NoError(t, err)
+
+			parsed, err := ParseCookies(text, format)
+			require.NoError(t, err)
+			require.Len(t, parsed, 2)
+			assert.Equal(t, "web_session", parsed[0].Name)
+			assert.True(t, parsed[0].HTTPOnly)
+			assert.True(t, parsed[1].Session)
+		})
+	}
+
+	header, err := ExportCookies(cks, FormatHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "a1=xyz; web_session=abc", header)
+}
@@ -4,3 +4,4 @@ import "errors"
 
 var ErrNoFeeds = errors.New("没有捕获到 feeds 数据")
 var ErrNoFeedDetail = errors.New("没有捕获到 feed 详情数据")
+var ErrLoginRequired = errors.New("未登录或登录已失效，请重新登录")
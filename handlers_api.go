@@ -1,11 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jobqueue"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 
 	"github.com/gin-gonic/gin"
@@ -82,6 +84,37 @@ func (s *AppServer) deleteCookiesHandler(c *gin.Context) {
 	}, "删除 cookies 成功")
 }
 
+// importCookiesHandler 导入 cookies（Netscape cookies.txt / EditThisCookie JSON / Cookie 头）
+func (s *AppServer) importCookiesHandler(c *gin.Context) {
+	var req ImportCookiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ImportCookies(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "IMPORT_COOKIES_FAILED",
+			"导入 cookies 失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "导入 cookies 成功")
+}
+
+// exportCookiesHandler 导出 cookies
+func (s *AppServer) exportCookiesHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.ExportCookies(c.Request.Context(), c.Query("format"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "EXPORT_COOKIES_FAILED",
+			"导出 cookies 失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "导出 cookies 成功")
+}
+
 // publishHandler 发布内容
 func (s *AppServer) publishHandler(c *gin.Context) {
 	var req PublishRequest
@@ -91,11 +124,20 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 		return
 	}
 
+	if req.Async || req.ScheduleMode == scheduleModeLocal {
+		job, err := s.xiaohongshuService.SubmitPublishJob(&req)
+		if err != nil {
+			respondPublishError(c, "PUBLISH_FAILED", "提交发布任务失败", err)
+			return
+		}
+		respondSuccess(c, job, jobSubmittedMessage(job, "发布任务已提交"))
+		return
+	}
+
 	// 执行发布
 	result, err := s.xiaohongshuService.PublishContent(c.Request.Context(), &req)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
-			"发布失败", err.Error())
+		respondPublishError(c, "PUBLISH_FAILED", "发布失败", err)
 		return
 	}
 
@@ -111,17 +153,284 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 		return
 	}
 
+	if req.Async || req.ScheduleMode == scheduleModeLocal {
+		job, err := s.xiaohongshuService.SubmitPublishVideoJob(c.Request.Context(), &req)
+		if err != nil {
+			respondPublishError(c, "PUBLISH_VIDEO_FAILED", "提交视频发布任务失败", err)
+			return
+		}
+		respondSuccess(c, job, jobSubmittedMessage(job, "视频发布任务已提交"))
+		return
+	}
+
 	// 执行视频发布
 	result, err := s.xiaohongshuService.PublishVideo(c.Request.Context(), &req)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_VIDEO_FAILED",
-			"视频发布失败", err.Error())
+		respondPublishError(c, "PUBLISH_VIDEO_FAILED", "视频发布失败", err)
 		return
 	}
 
 	respondSuccess(c, result, "视频发布成功")
 }
 
+// suggestTopicsHandler 获取话题联想
+func (s *AppServer) suggestTopicsHandler(c *gin.Context) {
+	keyword := c.Query("keyword")
+	if keyword == "" {
+		respondError(c, http.StatusBadRequest, "MISSING_KEYWORD",
+			"缺少关键词参数", "keyword parameter is required")
+		return
+	}
+
+	result, err := s.xiaohongshuService.SuggestTopics(c.Request.Context(), keyword)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "SUGGEST_TOPICS_FAILED",
+			"获取话题联想失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "获取话题联想成功")
+}
+
+// validateNoteHandler 按编辑器规则校验笔记，不打开浏览器
+func (s *AppServer) validateNoteHandler(c *gin.Context) {
+	var req ValidateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result := s.xiaohongshuService.ValidateNote(&req)
+	respondSuccess(c, result, "校验完成")
+}
+
+// lintTextHandler 按敏感词词库检查文本，不发布
+func (s *AppServer) lintTextHandler(c *gin.Context) {
+	var req LintTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.LintText(&req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "检查完成")
+}
+
+// getJobHandler 查询异步任务的状态、进度和结果
+func (s *AppServer) getJobHandler(c *gin.Context) {
+	job, err := s.xiaohongshuService.GetJob(c.Param("job_id"))
+	if err != nil {
+		respondJobError(c, "GET_JOB_FAILED", "查询任务失败", err)
+		return
+	}
+
+	respondSuccess(c, job, "查询任务成功")
+}
+
+// listJobsHandler 列出异步任务，可按 status 过滤
+func (s *AppServer) listJobsHandler(c *gin.Context) {
+	limit, err := parsePositiveLimit(c.Query("limit"), 20)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_LIMIT",
+			"limit 参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListJobs(c.Query("status"), limit)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "LIST_JOBS_FAILED",
+			"获取任务列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "获取任务列表成功")
+}
+
+// rescheduleJobHandler 修改本地定时发布的时间
+func (s *AppServer) rescheduleJobHandler(c *gin.Context) {
+	var req RescheduleJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	job, err := s.xiaohongshuService.RescheduleJob(c.Param("job_id"), req.ScheduleAt)
+	if err != nil {
+		respondJobError(c, "RESCHEDULE_JOB_FAILED", "修改定时发布时间失败", err)
+		return
+	}
+
+	respondSuccess(c, job, "修改定时发布时间成功")
+}
+
+// cancelJobHandler 取消定时、等待中或执行中的任务
+func (s *AppServer) cancelJobHandler(c *gin.Context) {
+	job, err := s.xiaohongshuService.CancelJob(c.Param("job_id"))
+	if err != nil {
+		respondJobError(c, "CANCEL_JOB_FAILED", "取消任务失败", err)
+		return
+	}
+
+	respondSuccess(c, job, "取消任务成功")
+}
+
+// respondPublishError idempotency_key 冲突时返回 409，其余返回 500
+func respondPublishError(c *gin.Context, code, message string, err error) {
+	if isIdempotencyConflict(err) {
+		respondError(c, http.StatusConflict, "IDEMPOTENCY_CONFLICT", message, err.Error())
+		return
+	}
+	respondError(c, http.StatusInternalServerError, code, message, err.Error())
+}
+
+// respondJobError 任务不存在时返回 404，任务功能不可用时返回 500，其余返回 400
+func respondJobError(c *gin.Context, code, message string, err error) {
+	switch {
+	case errors.Is(err, jobqueue.ErrNotFound):
+		respondError(c, http.StatusNotFound, "JOB_NOT_FOUND", "任务不存在", c.Param("job_id"))
+	case errors.Is(err, errJobsUnavailable):
+		respondError(c, http.StatusInternalServerError, code, message, err.Error())
+	default:
+		respondError(c, http.StatusBadRequest, code, message, err.Error())
+	}
+}
+
+// uploadMediaHandler 上传图片或视频（multipart 字段 file），返回可以在发布接口中使用的 media_id
+func (s *AppServer) uploadMediaHandler(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "MISSING_FILE",
+			"缺少上传文件", "multipart field 'file' is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "UPLOAD_MEDIA_FAILED",
+			"读取上传文件失败", err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, err := s.xiaohongshuService.SaveMedia(fileHeader.Filename, file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "UPLOAD_MEDIA_FAILED",
+			"上传文件失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "上传文件成功")
+}
+
+// listDraftsHandler 获取草稿箱列表
+func (s *AppServer) listDraftsHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.ListDrafts(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_DRAFTS_FAILED",
+			"获取草稿列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "获取草稿列表成功")
+}
+
+// publishDraftHandler 发布草稿
+func (s *AppServer) publishDraftHandler(c *gin.Context) {
+	var req PublishDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.PublishDraft(c.Request.Context(), req.DraftID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "PUBLISH_DRAFT_FAILED",
+			"发布草稿失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "发布草稿成功")
+}
+
+// deleteDraftHandler 删除草稿
+func (s *AppServer) deleteDraftHandler(c *gin.Context) {
+	draftID := c.Param("draft_id")
+	if err := s.xiaohongshuService.DeleteDraft(c.Request.Context(), draftID); err != nil {
+		respondError(c, http.StatusInternalServerError, "DELETE_DRAFT_FAILED",
+			"删除草稿失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"draft_id": draftID}, "删除草稿成功")
+}
+
+// listMyNotesHandler 获取自己发布的笔记列表
+func (s *AppServer) listMyNotesHandler(c *gin.Context) {
+	pageNum := 0
+	if pageParam := c.Query("page"); pageParam != "" {
+		parsed, err := strconv.Atoi(pageParam)
+		if err != nil || parsed < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"page 参数错误", "page must be a non-negative integer")
+			return
+		}
+		pageNum = parsed
+	}
+
+	result, err := s.xiaohongshuService.ListMyNotes(c.Request.Context(), c.Query("status"), pageNum)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_MY_NOTES_FAILED",
+			"获取我的笔记列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "获取我的笔记列表成功")
+}
+
+// editNoteHandler 编辑已发布的笔记
+func (s *AppServer) editNoteHandler(c *gin.Context) {
+	var req EditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	req.NoteID = c.Param("note_id")
+
+	result, err := s.xiaohongshuService.EditNote(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "EDIT_NOTE_FAILED",
+			"编辑笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "编辑笔记成功")
+}
+
+// deleteNoteHandler 删除自己发布的笔记
+func (s *AppServer) deleteNoteHandler(c *gin.Context) {
+	noteID := c.Param("note_id")
+
+	result, err := s.xiaohongshuService.DeleteNote(c.Request.Context(), noteID, c.Query("xsec_token"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "DELETE_NOTE_FAILED",
+			"删除笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "删除笔记成功")
+}
+
 // listFeedsHandler 获取Feeds列表
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
 	// 获取 Feeds 列表
@@ -274,10 +583,9 @@ func (s *AppServer) postCommentHandler(c *gin.Context) {
 	}
 
 	// 发表评论
-	result, err := s.xiaohongshuService.PostCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.Content)
+	result, err := s.xiaohongshuService.PostCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.Content, req.Mentions, req.IdempotencyKey)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
-			"发表评论失败", err.Error())
+		respondPublishError(c, "POST_COMMENT_FAILED", "发表评论失败", err)
 		return
 	}
 
@@ -294,10 +602,9 @@ func (s *AppServer) replyCommentHandler(c *gin.Context) {
 		return
 	}
 
-	result, err := s.xiaohongshuService.ReplyCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Content)
+	result, err := s.xiaohongshuService.ReplyCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Content, req.Mentions, req.IdempotencyKey)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "REPLY_COMMENT_FAILED",
-			"回复评论失败", err.Error())
+		respondPublishError(c, "REPLY_COMMENT_FAILED", "回复评论失败", err)
 		return
 	}
 
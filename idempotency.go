@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/idempotency"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// idempotency_key 的作用范围，不同操作使用同一个 key 互不影响
+const (
+	idempotencyScopePublishContent = "publish_content"
+	idempotencyScopePublishVideo   = "publish_video"
+	idempotencyScopePostComment    = "post_comment"
+	idempotencyScopeReplyComment   = "reply_comment"
+	idempotencyScopeJobSuffix      = "_job" // async 或 local 定时提交的任务
+)
+
+// errIdempotencyUnavailable 记录目录初始化失败时返回，避免在没有去重的情况下重复发布
+var errIdempotencyUnavailable = errors.New("idempotency_key 不可用：记录目录初始化失败，请查看服务日志")
+
+// openIdempotency 打开 idempotency_key 记录目录
+func openIdempotency() *idempotency.Store {
+	store, err := idempotency.Open(configs.GetIdempotencyPath(), configs.GetIdempotencyTTL())
+	if err != nil {
+		logrus.Warnf("初始化 idempotency 记录目录失败，带 idempotency_key 的请求不可用: %v", err)
+		return nil
+	}
+	return store
+}
+
+// withIdempotency 按 idempotency_key 执行 fn：key 为空时直接执行；同一个 key 已成功执行过时返回保存的结果，
+// replayed 为 true。request 用于检查同一个 key 是否被用在内容不同的请求上，不应包含 key 本身。
+//
+// 设置了 key 时 fn 不随 ctx 取消：HTTP 客户端超时断开后仍然执行完并保存结果，重试时直接返回，
+// 否则点击发布之后被取消会丢掉结果，重试时重复发布。已提交但无法确认结果时记录为中断，重试返回冲突
+func withIdempotency[T any](ctx context.Context, s *XiaohongshuService, scope, key string, request any, fn func(context.Context) (T, error)) (result T, replayed bool, err error) {
+	if key == "" {
+		result, err = fn(ctx)
+		return result, false, err
+	}
+	if s.idempotency == nil {
+		return result, false, errIdempotencyUnavailable
+	}
+
+	fingerprint, err := idempotency.Fingerprint(request)
+	if err != nil {
+		return result, false, err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	return idempotency.Do(s.idempotency, scope, key, fingerprint, func() (T, error) {
+		result, err := fn(ctx)
+		if errors.Is(err, xiaohongshu.ErrSubmitUnconfirmed) {
+			return result, idempotency.Unconfirmed(err)
+		}
+		return result, err
+	})
+}
+
+// isIdempotencyConflict 是否因为 idempotency_key 冲突而没有执行
+func isIdempotencyConflict(err error) bool {
+	return errors.Is(err, idempotency.ErrInProgress) ||
+		errors.Is(err, idempotency.ErrKeyReused) ||
+		errors.Is(err, idempotency.ErrInterrupted)
+}
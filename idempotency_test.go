@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/idempotency"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+func TestWithIdempotency(t *testing.T) {
+	store, err := idempotency.Open(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("idempotency.Open() unexpected error: %v", err)
+	}
+	s := &XiaohongshuService{idempotency: store}
+
+	t.Run("not canceled with the request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got, _, err := withIdempotency(ctx, s, "publish", "k1", "req", func(ctx context.Context) (string, error) {
+			return "ok", ctx.Err()
+		})
+		if err != nil || got != "ok" {
+			t.Fatalf("withIdempotency() = %q, %v; want ok, nil", got, err)
+		}
+	})
+
+	t.Run("unconfirmed submit is not retried", func(t *testing.T) {
+		unconfirmed := func(ctx context.Context) (string, error) {
+			return "", fmt.Errorf("发布失败: %w", xiaohongshu.ErrSubmitUnconfirmed)
+		}
+		if _, _, err := withIdempotency(context.Background(), s, "publish", "k2", "req", unconfirmed); !errors.Is(err, xiaohongshu.ErrSubmitUnconfirmed) {
+			t.Fatalf("withIdempotency() error = %v, want ErrSubmitUnconfirmed", err)
+		}
+
+		calls := 0
+		_, _, err := withIdempotency(context.Background(), s, "publish", "k2", "req", func(ctx context.Context) (string, error) {
+			calls++
+			return "dup", nil
+		})
+		if !errors.Is(err, idempotency.ErrInterrupted) || calls != 0 {
+			t.Fatalf("retry error = %v, calls = %d; want ErrInterrupted without running", err, calls)
+		}
+	})
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jobqueue"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mediastore"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/videoprobe"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 异步任务类型
+const (
+	jobKindPublishContent = "publish_content"
+	jobKindPublishVideo   = "publish_video"
+)
+
+// 定时发布方式
+const (
+	scheduleModePlatform = "platform" // 在发布页设置定时发布，由平台在 1 小时至 14 天内发布（默认）
+	scheduleModeLocal    = "local"    // 服务保存发布请求，到时间再打开浏览器发布，不限时间范围
+)
+
+// errJobsUnavailable 任务目录初始化失败时返回
+var errJobsUnavailable = errors.New("异步发布不可用：任务目录初始化失败，请查看服务日志")
+
+// RescheduleJobRequest 修改定时发布时间请求
+type RescheduleJobRequest struct {
+	ScheduleAt string `json:"schedule_at" binding:"required"` // 新的发布时间，ISO8601格式
+}
+
+// JobsListResponse 任务列表响应
+type JobsListResponse struct {
+	Jobs  []*jobqueue.Job `json:"jobs"`
+	Count int             `json:"count"`
+}
+
+// startJobs 加载任务目录并启动 worker。只有一个 worker，任务按提交顺序逐个执行，
+// 避免同一账号同时打开多个发布页面
+func (s *XiaohongshuService) startJobs() {
+	queue, err := jobqueue.Open(configs.GetJobsPath(), s.runJob)
+	if err != nil {
+		logrus.Warnf("初始化任务目录失败，异步发布不可用: %v", err)
+		return
+	}
+	queue.Start(context.Background())
+	s.jobs = queue
+}
+
+// SubmitPublishJob 预检后提交发布图文任务，立即返回 job_id。
+// media_id 对应的图片先复制到任务目录；schedule_mode 为 local 时所有图片都先保存到任务目录，到 schedule_at 再发布。
+// 设置了 idempotency_key 时重复提交返回第一次提交的任务
+func (s *XiaohongshuService) SubmitPublishJob(req *PublishRequest) (*jobqueue.Job, error) {
+	fingerprint := *req
+	fingerprint.IdempotencyKey = ""
+	job, replayed, err := withIdempotency(context.Background(), s, idempotencyScopePublishContent+idempotencyScopeJobSuffix, req.IdempotencyKey, fingerprint, func(context.Context) (*jobqueue.Job, error) {
+		return s.submitPublishJob(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		return s.currentJob(job), nil
+	}
+	return job, nil
+}
+
+func (s *XiaohongshuService) submitPublishJob(req *PublishRequest) (*jobqueue.Job, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	local, err := checkScheduleMode(req.ScheduleMode, req.MissedPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err := s.prepareNote(xhsutil.Note{Title: req.Title, Content: req.Content, Tags: req.Tags, ImageCount: len(req.Images)}, req.ContentFormat, req.TitleOverflow); err != nil {
+		return nil, err
+	}
+
+	payload := *req
+	payload.Async = false
+	payload.IdempotencyKey = ""
+	if !local {
+		return s.jobs.SubmitWithFiles(jobKindPublishContent, req.Title, func(dir string) (any, error) {
+			images, err := s.stageMediaIDs(req.Images, mediastore.KindImage, dir, "image")
+			if err != nil {
+				return nil, err
+			}
+			payload.Images = images
+			return payload, nil
+		})
+	}
+
+	runAt, err := parseLocalScheduleTime(req.ScheduleAt, req.Draft)
+	if err != nil {
+		return nil, err
+	}
+	payload.ScheduleAt, payload.ScheduleMode, payload.MissedPolicy = "", "", ""
+	return s.jobs.Schedule(jobKindPublishContent, req.Title, runAt, req.MissedPolicy, func(dir string) (any, error) {
+		images, err := s.stageImages(req.Images, dir)
+		if err != nil {
+			return nil, err
+		}
+		payload.Images = images
+		return payload, nil
+	})
+}
+
+// SubmitPublishVideoJob 预检后提交发布视频任务，立即返回 job_id。
+// media_id 对应的视频和封面先复制到任务目录；schedule_mode 为 local 时视频和封面都先保存到任务目录，到 schedule_at 再发布。
+// 设置了 idempotency_key 时重复提交返回第一次提交的任务
+func (s *XiaohongshuService) SubmitPublishVideoJob(ctx context.Context, req *PublishVideoRequest) (*jobqueue.Job, error) {
+	fingerprint := *req
+	fingerprint.IdempotencyKey = ""
+	job, replayed, err := withIdempotency(context.Background(), s, idempotencyScopePublishVideo+idempotencyScopeJobSuffix, req.IdempotencyKey, fingerprint, func(context.Context) (*jobqueue.Job, error) {
+		return s.submitPublishVideoJob(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		return s.currentJob(job), nil
+	}
+	return job, nil
+}
+
+func (s *XiaohongshuService) submitPublishVideoJob(ctx context.Context, req *PublishVideoRequest) (*jobqueue.Job, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	if req.Video == "" {
+		return nil, fmt.Errorf("必须提供视频文件")
+	}
+	local, err := checkScheduleMode(req.ScheduleMode, req.MissedPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err := s.prepareNote(xhsutil.Note{Title: req.Title, Content: req.Content, Tags: req.Tags, Video: true}, req.ContentFormat, req.TitleOverflow); err != nil {
+		return nil, err
+	}
+
+	payload := *req
+	payload.Async = false
+	payload.IdempotencyKey = ""
+	if !local {
+		return s.jobs.SubmitWithFiles(jobKindPublishVideo, req.Title, func(dir string) (any, error) {
+			staged, err := s.stageMediaIDs([]string{req.Video}, mediastore.KindVideo, dir, "video")
+			if err != nil {
+				return nil, err
+			}
+			payload.Video = staged[0]
+			if req.Cover != "" {
+				covers, err := s.stageMediaIDs([]string{req.Cover}, mediastore.KindImage, dir, "cover")
+				if err != nil {
+					return nil, fmt.Errorf("保存封面图片失败: %w", err)
+				}
+				payload.Cover = covers[0]
+			}
+			return payload, nil
+		})
+	}
+
+	runAt, err := parseLocalScheduleTime(req.ScheduleAt, req.Draft)
+	if err != nil {
+		return nil, err
+	}
+	payload.ScheduleAt, payload.ScheduleMode, payload.MissedPolicy = "", "", ""
+	return s.jobs.Schedule(jobKindPublishVideo, req.Title, runAt, req.MissedPolicy, func(dir string) (any, error) {
+		video, err := s.stageVideo(ctx, req.Video, dir)
+		if err != nil {
+			return nil, err
+		}
+		payload.Video = video
+		if req.Cover != "" {
+			covers, err := s.stageImages([]string{req.Cover}, dir)
+			if err != nil {
+				return nil, fmt.Errorf("保存封面图片失败: %w", err)
+			}
+			payload.Cover = covers[0]
+		}
+		return payload, nil
+	})
+}
+
+// currentJob 返回任务的最新状态，重复提交时第一次保存的可能已经过时
+func (s *XiaohongshuService) currentJob(job *jobqueue.Job) *jobqueue.Job {
+	if s.jobs != nil {
+		if current, err := s.jobs.Get(job.ID); err == nil {
+			return current
+		}
+	}
+	return job
+}
+
+// RescheduleJob 修改本地定时发布的时间
+func (s *XiaohongshuService) RescheduleJob(id, scheduleAt string) (*jobqueue.Job, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	runAt, err := parseLocalScheduleTime(scheduleAt, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.jobs.Reschedule(id, runAt)
+}
+
+// GetJob 查询任务状态、进度和结果
+func (s *XiaohongshuService) GetJob(id string) (*jobqueue.Job, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	return s.jobs.Get(id)
+}
+
+// ListJobs 按提交时间倒序列出任务，status 为空时返回所有状态；
+// 只列定时任务时按执行时间先后排列，最先发布的在前
+func (s *XiaohongshuService) ListJobs(status string, limit int) (*JobsListResponse, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	switch status {
+	case "", jobqueue.StatusScheduled, jobqueue.StatusQueued, jobqueue.StatusRunning, jobqueue.StatusSucceeded, jobqueue.StatusFailed, jobqueue.StatusCanceled:
+	default:
+		return nil, fmt.Errorf("无效的任务状态: %s，可选 scheduled、queued、running、succeeded、failed、canceled", status)
+	}
+
+	if status != jobqueue.StatusScheduled {
+		jobs := s.jobs.List(status, limit)
+		return &JobsListResponse{Jobs: jobs, Count: len(jobs)}, nil
+	}
+
+	jobs := s.jobs.List(status, 0)
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].RunAt.Before(*jobs[j].RunAt) })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return &JobsListResponse{Jobs: jobs, Count: len(jobs)}, nil
+}
+
+// CancelJob 取消定时、等待中或执行中的任务
+func (s *XiaohongshuService) CancelJob(id string) (*jobqueue.Job, error) {
+	if s.jobs == nil {
+		return nil, errJobsUnavailable
+	}
+	return s.jobs.Cancel(id)
+}
+
+// runJob 执行一个发布任务，发布过程的每个步骤写入任务进度。
+// 已经点击了发布但无法确认结果时，任务标记为需要确认，不能直接重新提交
+func (s *XiaohongshuService) runJob(ctx context.Context, job *jobqueue.Job, report func(jobqueue.Progress)) (any, error) {
+	result, err := s.runPublishJob(ctx, job, report)
+	if errors.Is(err, xiaohongshu.ErrSubmitUnconfirmed) {
+		return result, jobqueue.Unconfirmed(err)
+	}
+	return result, err
+}
+
+func (s *XiaohongshuService) runPublishJob(ctx context.Context, job *jobqueue.Job, report func(jobqueue.Progress)) (any, error) {
+	ctx = xiaohongshu.WithProgress(ctx, func(step string, current, total int) {
+		report(jobqueue.Progress{Step: step, Current: current, Total: total})
+	})
+
+	switch job.Kind {
+	case jobKindPublishContent:
+		var req PublishRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, fmt.Errorf("解析任务参数失败: %w", err)
+		}
+		return s.PublishContent(ctx, &req)
+	case jobKindPublishVideo:
+		var req PublishVideoRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, fmt.Errorf("解析任务参数失败: %w", err)
+		}
+		return s.PublishVideo(ctx, &req)
+	default:
+		return nil, fmt.Errorf("未知的任务类型: %s", job.Kind)
+	}
+}
+
+// jobSubmittedMessage 提交任务后的提示，定时任务说明发布时间
+func jobSubmittedMessage(job *jobqueue.Job, submitted string) string {
+	if job.Status == jobqueue.StatusScheduled && job.RunAt != nil {
+		return "已保存定时发布，将在 " + job.RunAt.Format("2006-01-02 15:04") + " 由服务发布"
+	}
+	return submitted
+}
+
+// checkScheduleMode 校验 schedule_mode 和 missed_policy，返回是否由服务定时发布
+func checkScheduleMode(mode, missedPolicy string) (bool, error) {
+	switch mode {
+	case "", scheduleModePlatform:
+		if missedPolicy != "" {
+			return false, fmt.Errorf("missed_policy 只能与 schedule_mode=local 一起使用")
+		}
+		return false, nil
+	case scheduleModeLocal:
+		return true, nil
+	default:
+		return false, fmt.Errorf("无效的 schedule_mode: %s，可选 platform、local", mode)
+	}
+}
+
+// parseLocalScheduleTime 解析本地定时发布时间，只要求是将来的时间
+func parseLocalScheduleTime(scheduleAt string, draft bool) (time.Time, error) {
+	if scheduleAt == "" {
+		return time.Time{}, fmt.Errorf("schedule_mode=local 时必须设置 schedule_at")
+	}
+	if draft {
+		return time.Time{}, fmt.Errorf("保存草稿时不能设置定时发布时间")
+	}
+	t, err := time.Parse(time.RFC3339, scheduleAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("定时发布时间格式错误，请使用 ISO8601 格式: %v", err)
+	}
+	if !t.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("定时发布时间必须晚于当前时间，当前设置: %s", t.Format("2006-01-02 15:04"))
+	}
+	return t, nil
+}
+
+// stageMediaIDs 把 media_id 对应的文件复制到任务目录，任务排队期间上传的文件过期也不影响发布。
+// 链接、base64 图片和本地路径保持原样，执行时再处理
+func (s *XiaohongshuService) stageMediaIDs(items []string, kind, dir, prefix string) ([]string, error) {
+	staged := make([]string, len(items))
+	for i, item := range items {
+		if !mediastore.IsMediaID(item) {
+			staged[i] = item
+			continue
+		}
+		path, err := s.resolveMedia(item, kind)
+		if err != nil {
+			return nil, err
+		}
+		dst := filepath.Join(dir, fmt.Sprintf("%s_%02d%s", prefix, i+1, strings.ToLower(filepath.Ext(path))))
+		if err := copyFile(path, dst); err != nil {
+			return nil, fmt.Errorf("保存 %s 失败: %w", item, err)
+		}
+		staged[i] = dst
+	}
+	return staged, nil
+}
+
+// stageImages 下载、解码或复制图片到定时任务目录，链接和临时文件到发布时可能已经失效
+func (s *XiaohongshuService) stageImages(images []string, dir string) ([]string, error) {
+	paths, err := s.processImages(images)
+	if err != nil {
+		return nil, err
+	}
+
+	staged := make([]string, len(paths))
+	for i, path := range paths {
+		dst := filepath.Join(dir, fmt.Sprintf("image_%02d%s", i+1, strings.ToLower(filepath.Ext(path))))
+		if err := copyFile(path, dst); err != nil {
+			return nil, fmt.Errorf("保存第%d张图片失败: %w", i+1, err)
+		}
+		staged[i] = dst
+	}
+	return staged, nil
+}
+
+// stageVideo 把视频保存到定时任务目录，并提前检查是否符合发布要求
+func (s *XiaohongshuService) stageVideo(ctx context.Context, video, dir string) (string, error) {
+	dst := filepath.Join(dir, "video"+strings.ToLower(filepath.Ext(video)))
+	switch {
+	case mediastore.IsMediaID(video):
+		path, err := s.resolveMedia(video, mediastore.KindVideo)
+		if err != nil {
+			return "", err
+		}
+		dst = filepath.Join(dir, "video"+filepath.Ext(path))
+		if err := copyFile(path, dst); err != nil {
+			return "", fmt.Errorf("保存视频失败: %w", err)
+		}
+	case downloader.IsVideoURL(video):
+		path, err := downloadVideo(ctx, video)
+		if err != nil {
+			return "", err
+		}
+		dst = filepath.Join(dir, "video"+filepath.Ext(path))
+		if err := moveFile(path, dst); err != nil {
+			return "", fmt.Errorf("保存视频失败: %w", err)
+		}
+	default:
+		if err := copyFile(video, dst); err != nil {
+			return "", fmt.Errorf("视频文件不存在或不可访问: %w", err)
+		}
+	}
+
+	info, err := videoprobe.Probe(dst)
+	if err != nil {
+		return "", fmt.Errorf("视频文件检查失败: %w", err)
+	}
+	if err := videoprobe.Validate(info, videoprobe.DefaultLimits); err != nil {
+		return "", fmt.Errorf("视频不符合发布要求: %w", err)
+	}
+	return dst, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// moveFile 移动文件，跨文件系统时复制后删除原文件
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
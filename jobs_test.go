@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mediastore"
+)
+
+func TestCheckScheduleMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		mode         string
+		missedPolicy string
+		wantLocal    bool
+		wantErr      bool
+	}{
+		{name: "empty uses platform", mode: ""},
+		{name: "platform", mode: "platform"},
+		{name: "local", mode: "local", wantLocal: true},
+		{name: "local with missed policy", mode: "local", missedPolicy: "skip", wantLocal: true},
+		{name: "missed policy without local", mode: "", missedPolicy: "skip", wantErr: true},
+		{name: "unknown mode", mode: "server", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			local, err := checkScheduleMode(tt.mode, tt.missedPolicy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("checkScheduleMode(%q, %q) expected error, got nil", tt.mode, tt.missedPolicy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkScheduleMode(%q, %q) unexpected error: %v", tt.mode, tt.missedPolicy, err)
+			}
+			if local != tt.wantLocal {
+				t.Fatalf("checkScheduleMode(%q, %q) = %v, want %v", tt.mode, tt.missedPolicy, local, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestParseLocalScheduleTime(t *testing.T) {
+	t.Parallel()
+
+	soon := time.Now().Add(10 * time.Minute).Format(time.RFC3339)
+	months := time.Now().AddDate(0, 3, 0).Format(time.RFC3339)
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		scheduleAt string
+		draft      bool
+		wantErr    bool
+	}{
+		{name: "under one hour", scheduleAt: soon},
+		{name: "months ahead", scheduleAt: months},
+		{name: "past", scheduleAt: past, wantErr: true},
+		{name: "empty", scheduleAt: "", wantErr: true},
+		{name: "bad format", scheduleAt: "2024-01-20 10:30", wantErr: true},
+		{name: "draft", scheduleAt: soon, draft: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseLocalScheduleTime(tt.scheduleAt, tt.draft)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseLocalScheduleTime(%q, %v) expected error, got nil", tt.scheduleAt, tt.draft)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLocalScheduleTime(%q, %v) unexpected error: %v", tt.scheduleAt, tt.draft, err)
+			}
+			if got.Format(time.RFC3339) != tt.scheduleAt {
+				t.Fatalf("parseLocalScheduleTime(%q, %v) = %s", tt.scheduleAt, tt.draft, got.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestAcquirePublish(t *testing.T) {
+	t.Parallel()
+
+	s := &XiaohongshuService{publishSlot: make(chan struct{}, 1)}
+	release, err := s.acquirePublish(context.Background())
+	if err != nil {
+		t.Fatalf("acquirePublish() unexpected error: %v", err)
+	}
+
+	// 已有发布进行中时，另一个发布要等待，调用方取消后返回错误
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.acquirePublish(ctx); err == nil {
+		t.Fatalf("acquirePublish() while another publish is running expected error, got nil")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := s.acquirePublish(context.Background())
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+	release()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("acquirePublish() did not proceed after the previous publish finished")
+	}
+}
+
+func TestStageMediaIDs(t *testing.T) {
+	t.Parallel()
+
+	media, err := mediastore.New(t.TempDir(), time.Hour, mediastore.Limits{})
+	if err != nil {
+		t.Fatalf("mediastore.New() unexpected error: %v", err)
+	}
+	item, err := media.Save("photo.png", strings.NewReader("\x89PNG\r\n\x1a\n0000IHDR"))
+	if err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	s := &XiaohongshuService{media: media}
+
+	dir := t.TempDir()
+	got, err := s.stageMediaIDs([]string{"https://example.com/a.jpg", item.ID}, mediastore.KindImage, dir, "image")
+	if err != nil {
+		t.Fatalf("stageMediaIDs() unexpected error: %v", err)
+	}
+	if got[0] != "https://example.com/a.jpg" {
+		t.Fatalf("stageMediaIDs() changed a link: %q", got[0])
+	}
+	if filepath.Dir(got[1]) != dir {
+		t.Fatalf("stageMediaIDs() = %q, want a copy in %s", got[1], dir)
+	}
+
+	if _, err := s.stageMediaIDs([]string{item.ID}, mediastore.KindVideo, dir, "video"); err == nil {
+		t.Fatalf("stageMediaIDs() with an image as video expected error, got nil")
+	}
+
+	// 复制后原文件过期删除，任务仍然可以使用
+	if err := os.Remove(item.Path()); err != nil {
+		t.Fatalf("os.Remove() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(got[1]); err != nil {
+		t.Fatalf("staged file missing: %v", err)
+	}
+}
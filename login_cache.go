@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+)
+
+const loginStatusCacheTTL = 5 * time.Minute
+
+// loginStatusCache 缓存登录状态检查结果，避免每次检查都启动浏览器。
+// 缓存在 TTL 到期、cookies 文件变化或被显式失效时作废。
+type loginStatusCache struct {
+	mu sync.Mutex
+
+	ttl        time.Duration
+	cookiePath func() string
+	now        func() time.Time
+
+	status      *LoginStatusResponse
+	checkedAt   time.Time
+	fingerprint cookieFingerprint
+}
+
+// cookieFingerprint cookies 文件的指纹，用于感知外部对 cookies 的修改
+type cookieFingerprint struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func newLoginStatusCache(ttl time.Duration) *loginStatusCache {
+	return &loginStatusCache{
+		ttl:        ttl,
+		cookiePath: cookies.GetCookiesFilePath,
+		now:        time.Now,
+	}
+}
+
+// Get 返回仍然有效的缓存结果
+func (c *loginStatusCache) Get() (*LoginStatusResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status == nil {
+		return nil, false
+	}
+
+	if c.now().Sub(c.checkedAt) > c.ttl || c.currentFingerprint() != c.fingerprint {
+		c.status = nil
+		return nil, false
+	}
+
+	status := *c.status
+	status.Cached = true
+	return &status, true
+}
+
+// Set 写入新的检查结果
+func (c *loginStatusCache) Set(status *LoginStatusResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	copied := *status
+	c.status = &copied
+	c.checkedAt = c.now()
+	c.fingerprint = c.currentFingerprint()
+}
+
+// Invalidate 使缓存失效（cookies 变化、检测到登录墙等）
+func (c *loginStatusCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status = nil
+}
+
+// CookiesExist 判断 cookies 文件是否存在
+func (c *loginStatusCache) CookiesExist() bool {
+	return c.currentFingerprint().exists
+}
+
+func (c *loginStatusCache) currentFingerprint() cookieFingerprint {
+	info, err := os.Stat(c.cookiePath())
+	if err != nil {
+		return cookieFingerprint{}
+	}
+
+	return cookieFingerprint{
+		exists:  true,
+		size:    info.Size(),
+		modTime: info.ModTime(),
+	}
+}
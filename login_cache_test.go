@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoginStatusCache(t *testing.T) {
+	t.Parallel()
+
+	cookiePath := filepath.Join(t.TempDir(), "cookies.json")
+	if err := os.WriteFile(cookiePath, []byte("[]"), 0644); err != nil {
+		t.Fatalf("write cookies: %v", err)
+	}
+
+	now := time.Now()
+	cache := newLoginStatusCache(time.Minute)
+	cache.cookiePath = func() string { return cookiePath }
+	cache.now = func() time.Time { return now }
+
+	if _, ok := cache.Get(); ok {
+		t.Fatalf("empty cache should miss")
+	}
+
+	cache.Set(&LoginStatusResponse{IsLoggedIn: true, Username: "tester"})
+
+	got, ok := cache.Get()
+	if !ok {
+		t.Fatalf("fresh cache should hit")
+	}
+	if !got.Cached || got.Username != "tester" {
+		t.Fatalf("unexpected cached status: %+v", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get(); ok {
+		t.Fatalf("expired cache should miss")
+	}
+
+	cache.Set(&LoginStatusResponse{IsLoggedIn: true})
+	if err := os.WriteFile(cookiePath, []byte(`[{"name":"a"}]`), 0644); err != nil {
+		t.Fatalf("rewrite cookies: %v", err)
+	}
+	if _, ok := cache.Get(); ok {
+		t.Fatalf("cache should miss after cookies changed")
+	}
+
+	cache.Set(&LoginStatusResponse{IsLoggedIn: true})
+	cache.Invalidate()
+	if _, ok := cache.Get(); ok {
+		t.Fatalf("cache should miss after invalidate")
+	}
+
+	if !cache.CookiesExist() {
+		t.Fatalf("cookies file should exist")
+	}
+}
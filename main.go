@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
@@ -13,10 +15,29 @@ func main() {
 		headless bool
 		binPath  string // 浏览器二进制文件路径
 		port     string
+
+		imageMaxEdge int
+		imageMaxMB   int
+		videoMaxMB   int
+		mediaTTL     time.Duration
+
+		lintWords string
+		lintMode  string
+		jobsDir   string
+
+		idempotencyTTL time.Duration
 	)
 	flag.BoolVar(&headless, "headless", true, "是否无头模式")
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
 	flag.StringVar(&port, "port", ":18060", "端口")
+	flag.IntVar(&imageMaxEdge, "image-max-edge", configs.DefaultImageMaxEdge, "上传前图片最长边上限（像素），0 表示不限制")
+	flag.IntVar(&imageMaxMB, "image-max-mb", configs.DefaultImageMaxMB, "上传前图片文件大小上限（MB），0 表示不限制")
+	flag.IntVar(&videoMaxMB, "video-max-mb", configs.DefaultVideoDownloadMaxMB, "从链接下载视频的大小上限（MB），0 表示不限制")
+	flag.DurationVar(&mediaTTL, "media-ttl", configs.DefaultMediaTTL, "通过 /api/v1/media 上传的文件保留时长，如 24h、30m")
+	flag.StringVar(&lintWords, "lint-words", "", "敏感词词库文件或目录，多个用逗号分隔，为空使用内置词库")
+	flag.StringVar(&lintMode, "lint-mode", configs.DefaultLintMode, "敏感词检查模式：block（命中 high 级别的词时阻止发布）| warn（只提示）| off")
+	flag.StringVar(&jobsDir, "jobs-dir", "", "异步发布任务的保存目录，为空使用系统临时目录下的 xiaohongshu_jobs")
+	flag.DurationVar(&idempotencyTTL, "idempotency-ttl", configs.DefaultIdempotencyTTL, "idempotency_key 的保留时长，期间用同一个 key 重试会返回第一次的结果，如 24h、72h")
 	flag.Parse()
 
 	if len(binPath) == 0 {
@@ -25,6 +46,17 @@ func main() {
 
 	configs.InitHeadless(headless)
 	configs.SetBinPath(binPath)
+	configs.SetImageLimits(imageMaxEdge, imageMaxMB)
+	configs.SetVideoDownloadMaxMB(videoMaxMB)
+	configs.SetMediaTTL(mediaTTL)
+	if lintWords != "" {
+		configs.SetLintWordFiles(strings.Split(lintWords, ","))
+	}
+	if err := configs.SetLintMode(lintMode); err != nil {
+		logrus.Fatalf("%v", err)
+	}
+	configs.SetJobsPath(jobsDir)
+	configs.SetIdempotencyTTL(idempotencyTTL)
 
 	// 初始化服务
 	xiaohongshuService := NewXiaohongshuService()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jobqueue"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
@@ -33,7 +34,8 @@ func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 	// 根据 IsLoggedIn 判断并返回友好的提示
 	var resultText string
 	if status.IsLoggedIn {
-		resultText = fmt.Sprintf("✅ 已登录\n用户名: %s\n\n你可以使用其他功能了。", status.Username)
+		resultText = fmt.Sprintf("✅ 已登录\n昵称: %s\n用户ID: %s\n小红书号: %s\n\n你可以使用其他功能了。",
+			status.Username, status.UserID, status.RedID)
 	} else {
 		resultText = fmt.Sprintf("❌ 未登录\n\n请使用 get_login_qrcode 工具获取二维码进行登录。")
 	}
@@ -108,6 +110,53 @@ func (s *AppServer) handleDeleteCookies(ctx context.Context) *MCPToolResult {
 	}
 }
 
+// handleImportCookies 处理导入 cookies
+func (s *AppServer) handleImportCookies(ctx context.Context, args ImportCookiesArgs) *MCPToolResult {
+	logrus.Info("MCP: 导入 cookies")
+
+	if strings.TrimSpace(args.Cookies) == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导入 cookies 失败: 缺少cookies参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.ImportCookies(ctx, &ImportCookiesRequest{
+		Cookies: args.Cookies,
+		Format:  args.Format,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导入 cookies 失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	resultText := fmt.Sprintf("✅ 已导入 %d 个 cookies（格式: %s），登录验证通过\n昵称: %s\n用户ID: %s",
+		result.Imported, result.Format, result.LoginStatus.Username, result.LoginStatus.UserID)
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: resultText}},
+	}
+}
+
+// handleExportCookies 处理导出 cookies
+func (s *AppServer) handleExportCookies(ctx context.Context, args ExportCookiesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 导出 cookies format=%s", args.Format)
+
+	result, err := s.xiaohongshuService.ExportCookies(ctx, args.Format)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导出 cookies 失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: result.Cookies}},
+	}
+}
+
 // handlePublishContent 处理发布内容
 func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]interface{}) *MCPToolResult {
 	logrus.Info("MCP: 发布内容")
@@ -134,16 +183,52 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 
 	// 解析定时发布参数
 	scheduleAt, _ := args["schedule_at"].(string)
-
-	logrus.Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d, 定时: %s", title, len(imagePaths), len(tags), scheduleAt)
+	draft, _ := args["draft"].(bool)
+	visibility, _ := args["visibility"].(string)
+	location, _ := args["location"].(string)
+	mentionsInterface, _ := args["mentions"].([]interface{})
+	imageAspect, _ := args["image_aspect"].(string)
+	imageFit, _ := args["image_fit"].(string)
+	titleOverflow, _ := args["title_overflow"].(string)
+	contentFormat, _ := args["content_format"].(string)
+	async, _ := args["async"].(bool)
+	scheduleMode, _ := args["schedule_mode"].(string)
+	missedPolicy, _ := args["missed_policy"].(string)
+	idempotencyKey, _ := args["idempotency_key"].(string)
+
+	logrus.Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d, 定时: %s, 草稿: %v", title, len(imagePaths), len(tags), scheduleAt, draft)
 
 	// 构建发布请求
 	req := &PublishRequest{
-		Title:      title,
-		Content:    content,
-		Images:     imagePaths,
-		Tags:       tags,
-		ScheduleAt: scheduleAt,
+		Title:          title,
+		Content:        content,
+		Images:         imagePaths,
+		Tags:           tags,
+		ScheduleAt:     scheduleAt,
+		ScheduleMode:   scheduleMode,
+		MissedPolicy:   missedPolicy,
+		Draft:          draft,
+		Visibility:     visibility,
+		Location:       location,
+		Mentions:       convertInterfacesToStrings(mentionsInterface),
+		ImageAspect:    imageAspect,
+		ImageFit:       imageFit,
+		ContentFormat:  contentFormat,
+		TitleOverflow:  titleOverflow,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	if async || scheduleMode == scheduleModeLocal {
+		job, err := s.xiaohongshuService.SubmitPublishJob(req)
+		if err != nil {
+			return &MCPToolResult{
+				Content: []MCPContent{{Type: "text", Text: "提交发布任务失败: " + err.Error()}},
+				IsError: true,
+			}
+		}
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: jobSubmittedText(job, "发布任务已提交") + ": " + formatResultJSON(job)}},
+		}
 	}
 
 	// 执行发布
@@ -158,7 +243,10 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		}
 	}
 
-	resultText := fmt.Sprintf("内容发布成功: %+v", result)
+	resultText := "内容发布成功" + replayedText(result.Replayed) + ": " + formatResultJSON(result)
+	if result.Draft {
+		resultText = "内容已保存到草稿箱" + replayedText(result.Replayed) + ": " + formatResultJSON(result)
+	}
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -167,9 +255,34 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 	}
 }
 
-// handlePublishVideo 处理发布视频内容（仅本地单个视频文件）
+// jobSubmittedText 提交任务后告诉调用方如何查询或修改任务
+func jobSubmittedText(job *jobqueue.Job, submitted string) string {
+	if job.Status == jobqueue.StatusScheduled {
+		return jobSubmittedMessage(job, submitted) + "，可用 list_scheduled_posts 查看、reschedule_post 修改时间、cancel_job 取消"
+	}
+	return submitted + "，使用 get_job 查询进度"
+}
+
+// replayedText 相同 idempotency_key 的请求已经执行过时提示调用方返回的是第一次的结果
+func replayedText(replayed bool) string {
+	if replayed {
+		return "（重复请求，返回第一次的结果）"
+	}
+	return ""
+}
+
+// formatResultJSON 把结果格式化为 JSON 文本，避免 %+v 输出指针地址
+func formatResultJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(data)
+}
+
+// handlePublishVideo 处理发布视频内容（本地文件或 HTTP 链接）
 func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: 发布视频内容（本地）")
+	logrus.Info("MCP: 发布视频内容")
 
 	title, _ := args["title"].(string)
 	content, _ := args["content"].(string)
@@ -187,7 +300,7 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发布失败: 缺少本地视频文件路径",
+				Text: "发布失败: 缺少视频文件路径或链接",
 			}},
 			IsError: true,
 		}
@@ -195,16 +308,52 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 
 	// 解析定时发布参数
 	scheduleAt, _ := args["schedule_at"].(string)
-
-	logrus.Infof("MCP: 发布视频 - 标题: %s, 标签数量: %d, 定时: %s", title, len(tags), scheduleAt)
+	draft, _ := args["draft"].(bool)
+	visibility, _ := args["visibility"].(string)
+	location, _ := args["location"].(string)
+	mentionsInterface, _ := args["mentions"].([]interface{})
+	cover, _ := args["cover"].(string)
+	coverAt, _ := args["cover_at"].(string)
+	titleOverflow, _ := args["title_overflow"].(string)
+	contentFormat, _ := args["content_format"].(string)
+	async, _ := args["async"].(bool)
+	scheduleMode, _ := args["schedule_mode"].(string)
+	missedPolicy, _ := args["missed_policy"].(string)
+	idempotencyKey, _ := args["idempotency_key"].(string)
+
+	logrus.Infof("MCP: 发布视频 - 标题: %s, 标签数量: %d, 定时: %s, 草稿: %v", title, len(tags), scheduleAt, draft)
 
 	// 构建发布请求
 	req := &PublishVideoRequest{
-		Title:      title,
-		Content:    content,
-		Video:      videoPath,
-		Tags:       tags,
-		ScheduleAt: scheduleAt,
+		Title:          title,
+		Content:        content,
+		Video:          videoPath,
+		Tags:           tags,
+		ScheduleAt:     scheduleAt,
+		ScheduleMode:   scheduleMode,
+		MissedPolicy:   missedPolicy,
+		Draft:          draft,
+		Visibility:     visibility,
+		Location:       location,
+		Mentions:       convertInterfacesToStrings(mentionsInterface),
+		Cover:          cover,
+		CoverAt:        coverAt,
+		ContentFormat:  contentFormat,
+		TitleOverflow:  titleOverflow,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	if async || scheduleMode == scheduleModeLocal {
+		job, err := s.xiaohongshuService.SubmitPublishVideoJob(ctx, req)
+		if err != nil {
+			return &MCPToolResult{
+				Content: []MCPContent{{Type: "text", Text: "提交视频发布任务失败: " + err.Error()}},
+				IsError: true,
+			}
+		}
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: jobSubmittedText(job, "视频发布任务已提交") + ": " + formatResultJSON(job)}},
+		}
 	}
 
 	// 执行发布
@@ -219,7 +368,10 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		}
 	}
 
-	resultText := fmt.Sprintf("视频发布成功: %+v", result)
+	resultText := "视频发布成功" + replayedText(result.Replayed) + ": " + formatResultJSON(result)
+	if result.Draft {
+		resultText = "视频已保存到草稿箱" + replayedText(result.Replayed) + ": " + formatResultJSON(result)
+	}
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -228,6 +380,389 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 	}
 }
 
+// handleValidateNote 处理校验笔记，不打开浏览器
+func (s *AppServer) handleValidateNote(_ context.Context, args ValidateNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 校验笔记 title=%s", args.Title)
+
+	result := s.xiaohongshuService.ValidateNote(&ValidateNoteRequest{
+		Title:   args.Title,
+		Content: args.Content,
+		Tags:    args.Tags,
+		Images:  args.Images,
+		Video:   args.Video,
+	})
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("校验完成，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleLintText 处理敏感词检查
+func (s *AppServer) handleLintText(_ context.Context, args LintTextArgs) *MCPToolResult {
+	logrus.Info("MCP: 敏感词检查")
+
+	result, err := s.xiaohongshuService.LintText(&LintTextRequest{
+		Text:    args.Text,
+		Title:   args.Title,
+		Content: args.Content,
+		Tags:    args.Tags,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "敏感词检查失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("检查完成，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleSuggestTopics 处理话题联想
+func (s *AppServer) handleSuggestTopics(ctx context.Context, args SuggestTopicsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 话题联想 keyword=%s", args.Keyword)
+
+	if args.Keyword == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取话题联想失败: 缺少keyword参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.SuggestTopics(ctx, args.Keyword)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取话题联想失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("获取话题联想成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleListDrafts 处理获取草稿箱列表
+func (s *AppServer) handleListDrafts(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取草稿箱列表")
+
+	result, err := s.xiaohongshuService.ListDrafts(ctx)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取草稿列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("获取草稿列表成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handlePublishDraft 处理发布草稿
+func (s *AppServer) handlePublishDraft(ctx context.Context, args DraftIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 发布草稿 draft_id=%s", args.DraftID)
+
+	if args.DraftID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "发布草稿失败: 缺少draft_id参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.PublishDraft(ctx, args.DraftID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "发布草稿失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("草稿发布成功: %+v", result)}},
+	}
+}
+
+// handleDeleteDraft 处理删除草稿
+func (s *AppServer) handleDeleteDraft(ctx context.Context, args DraftIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 删除草稿 draft_id=%s", args.DraftID)
+
+	if args.DraftID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除草稿失败: 缺少draft_id参数"}},
+			IsError: true,
+		}
+	}
+
+	if err := s.xiaohongshuService.DeleteDraft(ctx, args.DraftID); err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除草稿失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("草稿 %s 已删除", args.DraftID)}},
+	}
+}
+
+// handleGetJob 处理查询发布任务
+func (s *AppServer) handleGetJob(_ context.Context, args JobIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 查询任务 job_id=%s", args.JobID)
+
+	if args.JobID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "查询任务失败: 缺少job_id参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.GetJob(args.JobID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "查询任务失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("查询任务成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleListJobs 处理列出发布任务
+func (s *AppServer) handleListJobs(_ context.Context, args ListJobsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 列出任务 status=%s limit=%d", args.Status, args.Limit)
+
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	result, err := s.xiaohongshuService.ListJobs(args.Status, limit)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取任务列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("获取任务列表成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleListScheduledPosts 处理列出本地定时发布
+func (s *AppServer) handleListScheduledPosts(_ context.Context, args ListScheduledPostsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 列出定时发布 limit=%d", args.Limit)
+
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	result, err := s.xiaohongshuService.ListJobs(jobqueue.StatusScheduled, limit)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取定时发布列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("获取定时发布列表成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleReschedulePost 处理修改本地定时发布时间
+func (s *AppServer) handleReschedulePost(_ context.Context, args ReschedulePostArgs) *MCPToolResult {
+	logrus.Infof("MCP: 修改定时发布时间 job_id=%s schedule_at=%s", args.JobID, args.ScheduleAt)
+
+	if args.JobID == "" || args.ScheduleAt == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "修改定时发布时间失败: 缺少job_id或schedule_at参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.RescheduleJob(args.JobID, args.ScheduleAt)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "修改定时发布时间失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("任务 %s 将在 %s 发布", job.ID, job.RunAt.Format("2006-01-02 15:04"))}},
+	}
+}
+
+// handleCancelJob 处理取消发布任务
+func (s *AppServer) handleCancelJob(_ context.Context, args JobIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 取消任务 job_id=%s", args.JobID)
+
+	if args.JobID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消任务失败: 缺少job_id参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.CancelJob(args.JobID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消任务失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	text := fmt.Sprintf("任务 %s 已取消", args.JobID)
+	if job.Status == jobqueue.StatusRunning {
+		text = fmt.Sprintf("任务 %s 正在执行，已请求取消，当前操作中断后状态变为 canceled", args.JobID)
+	}
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: text}},
+	}
+}
+
+// handleListMyNotes 处理获取自己发布的笔记列表
+func (s *AppServer) handleListMyNotes(ctx context.Context, args ListMyNotesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取我的笔记列表 status=%s page=%d", args.Status, args.Page)
+
+	result, err := s.xiaohongshuService.ListMyNotes(ctx, args.Status, args.Page)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取我的笔记列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("获取我的笔记列表成功，但序列化失败: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: string(jsonData)}},
+	}
+}
+
+// handleEditNote 处理编辑已发布的笔记
+func (s *AppServer) handleEditNote(ctx context.Context, args EditNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 编辑笔记 note_id=%s title=%s", args.NoteID, args.Title)
+
+	if args.NoteID == "" || args.Title == "" || args.Content == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: 缺少note_id、title或content参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.EditNote(ctx, &EditNoteRequest{
+		NoteID:     args.NoteID,
+		Title:      args.Title,
+		Content:    args.Content,
+		Tags:       args.Tags,
+		Images:     args.Images,
+		ImageOrder: args.ImageOrder,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("笔记编辑成功: %+v", result)}},
+	}
+}
+
+// handleDeleteNote 处理删除自己的笔记
+func (s *AppServer) handleDeleteNote(ctx context.Context, args DeleteNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 删除笔记 note_id=%s", args.NoteID)
+
+	if args.NoteID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除笔记失败: 缺少note_id参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.DeleteNote(ctx, args.NoteID, args.XsecToken)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("笔记 %s 已删除", result.FeedID)}},
+	}
+}
+
 // handleListFeeds 处理获取Feeds列表
 func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
 	logrus.Info("MCP: 获取Feeds列表")
@@ -641,10 +1176,14 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 		}
 	}
 
-	logrus.Infof("MCP: 发表评论 - Feed ID: %s, 内容长度: %d", feedID, len(content))
+	mentionsInterface, _ := args["mentions"].([]interface{})
+	mentions := convertInterfacesToStrings(mentionsInterface)
+	idempotencyKey, _ := args["idempotency_key"].(string)
+
+	logrus.Infof("MCP: 发表评论 - Feed ID: %s, 内容长度: %d, @用户: %v", feedID, len(content), mentions)
 
 	// 发表评论
-	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content)
+	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content, mentions, idempotencyKey)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -656,7 +1195,7 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 	}
 
 	// 返回成功结果，只包含feed_id
-	resultText := fmt.Sprintf("评论发表成功 - Feed ID: %s", result.FeedID)
+	resultText := fmt.Sprintf("评论发表成功%s - Feed ID: %s", replayedText(result.Replayed), result.FeedID)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -715,10 +1254,14 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 		}
 	}
 
-	logrus.Infof("MCP: 回复评论 - Feed ID: %s, Comment ID: %s, User ID: %s, 内容长度: %d", feedID, commentID, userID, len(content))
+	mentionsInterface, _ := args["mentions"].([]interface{})
+	mentions := convertInterfacesToStrings(mentionsInterface)
+	idempotencyKey, _ := args["idempotency_key"].(string)
+
+	logrus.Infof("MCP: 回复评论 - Feed ID: %s, Comment ID: %s, User ID: %s, 内容长度: %d, @用户: %v", feedID, commentID, userID, len(content), mentions)
 
 	// 回复评论
-	result, err := s.xiaohongshuService.ReplyCommentToFeed(ctx, feedID, xsecToken, commentID, userID, content)
+	result, err := s.xiaohongshuService.ReplyCommentToFeed(ctx, feedID, xsecToken, commentID, userID, content, mentions, idempotencyKey)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -730,7 +1273,7 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 	}
 
 	// 返回成功结果
-	responseText := fmt.Sprintf("评论回复成功 - Feed ID: %s, Comment ID: %s, User ID: %s", result.FeedID, result.TargetCommentID, result.TargetUserID)
+	responseText := fmt.Sprintf("评论回复成功%s - Feed ID: %s, Comment ID: %s, User ID: %s", replayedText(result.Replayed), result.FeedID, result.TargetCommentID, result.TargetUserID)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -15,22 +15,128 @@ func boolPtr(b bool) *bool { return &b }
 
 // MCP 工具参数结构体定义
 
+// ImportCookiesArgs 导入 cookies 的参数
+type ImportCookiesArgs struct {
+	Cookies string `json:"cookies" jsonschema:"cookies 内容，支持 Netscape cookies.txt、EditThisCookie 导出的 JSON、原始 Cookie 请求头（如 a=1; b=2）三种格式"`
+	Format  string `json:"format,omitempty" jsonschema:"cookies 格式: auto|netscape|json|header，默认 auto 自动识别"`
+}
+
+// ExportCookiesArgs 导出 cookies 的参数
+type ExportCookiesArgs struct {
+	Format string `json:"format,omitempty" jsonschema:"导出格式: netscape|json|header，默认 json（EditThisCookie 格式）"`
+}
+
 // PublishContentArgs 发布内容的参数
 type PublishContentArgs struct {
-	Title      string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content    string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Images     []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
-	Tags       []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
-	ScheduleAt string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
+	Title          string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词，超长时见 title_overflow）"`
+	Content        string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Images         []string `json:"images" jsonschema:"图片列表（至少需要1张图片）。支持：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）或 file:// 链接；3. base64 图片，如 data:image/png;base64,... 或 image/png;base64,...（适合客户端与服务不在同一台机器时，单张最大20MB）；4. 通过 POST /api/v1/media 上传后得到的 media_id"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	ScheduleAt     string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，默认由平台定时发布，支持1小时至14天内；超出范围时配合 schedule_mode=local 使用。不填则立即发布"`
+	ScheduleMode   string   `json:"schedule_mode,omitempty" jsonschema:"定时方式（可选）: platform 在发布页设置定时，由平台发布（默认，1小时至14天）| local 服务保存请求和媒体文件，到时间再发布，不限时间范围，需要服务届时保持运行"`
+	MissedPolicy   string   `json:"missed_policy,omitempty" jsonschema:"local 定时发布时服务没有运行、错过了发布时间的处理方式（可选）: run 服务恢复后立即发布（默认）| skip 不再发布"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"是否只保存到草稿箱而不发布（可选），默认false。保存后可用 list_drafts 查看、publish_draft 发布"`
+	Visibility     string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Location       string   `json:"location,omitempty" jsonschema:"地点关键词（可选），如门店名称，自动选择最匹配的地点，匹配不到时返回候选地点列表"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"要@的用户列表（可选），每项为昵称、用户ID或 昵称:用户ID。正文中写了 @昵称 的会在原位置插入，其余追加到正文末尾"`
+	ImageAspect    string   `json:"image_aspect,omitempty" jsonschema:"上传前把图片调整为指定宽高比（可选）: 3:4|1:1|4:3，不填则保持原比例"`
+	ImageFit       string   `json:"image_fit,omitempty" jsonschema:"调整宽高比的方式（可选）: pad 白色填充（默认）| crop 居中裁剪"`
+	ContentFormat  string   `json:"content_format,omitempty" jsonschema:"正文格式（可选）: plain 原样输入（默认）| markdown 先把 Markdown 转换为小红书的纯文本风格：标题变为 emoji 开头的行，列表变为 emoji 项目符号，去掉粗体等标记和链接，正文中的 #标签 移到 tags"`
+	TitleOverflow  string   `json:"title_overflow,omitempty" jsonschema:"标题超过20字时的处理方式（可选）: error 返回错误（默认）| truncate 截断标题 | move_to_body 截断标题并把完整标题放到正文开头"`
+	Async          bool     `json:"async,omitempty" jsonschema:"是否提交为后台任务（可选），默认false。为true时立即返回 job_id，用 get_job 查询进度和结果，避免发布耗时过长导致调用超时"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），调用超时后用同一个 key 重试时不会重复发布，直接返回第一次的结果。内容不同的请求不能复用同一个 key"`
 }
 
-// PublishVideoArgs 发布视频的参数（仅支持本地单个视频文件）
+// PublishVideoArgs 发布视频的参数（单个视频文件）
 type PublishVideoArgs struct {
-	Title      string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content    string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Video      string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
-	Tags       []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
-	ScheduleAt string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
+	Title          string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词，超长时见 title_overflow）"`
+	Content        string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Video          string   `json:"video" jsonschema:"单个视频文件：本地绝对路径（如:/Users/user/video.mp4）、HTTP(S) 链接或通过 POST /api/v1/media 上传后得到的 media_id，仅支持 MP4/MOV"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	ScheduleAt     string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，默认由平台定时发布，支持1小时至14天内；超出范围时配合 schedule_mode=local 使用。不填则立即发布"`
+	ScheduleMode   string   `json:"schedule_mode,omitempty" jsonschema:"定时方式（可选）: platform 在发布页设置定时，由平台发布（默认，1小时至14天）| local 服务保存请求和媒体文件，到时间再发布，不限时间范围，需要服务届时保持运行"`
+	MissedPolicy   string   `json:"missed_policy,omitempty" jsonschema:"local 定时发布时服务没有运行、错过了发布时间的处理方式（可选）: run 服务恢复后立即发布（默认）| skip 不再发布"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"是否只保存到草稿箱而不发布（可选），默认false。保存后可用 list_drafts 查看、publish_draft 发布"`
+	Visibility     string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Location       string   `json:"location,omitempty" jsonschema:"地点关键词（可选），如门店名称，自动选择最匹配的地点，匹配不到时返回候选地点列表"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"要@的用户列表（可选），每项为昵称、用户ID或 昵称:用户ID。正文中写了 @昵称 的会在原位置插入，其余追加到正文末尾"`
+	Cover          string   `json:"cover,omitempty" jsonschema:"封面图片（可选），HTTP链接或本地绝对路径，不填由平台自动选择"`
+	CoverAt        string   `json:"cover_at,omitempty" jsonschema:"按视频时间点截取封面（可选），如 3、2.5s、00:01:20，不能与 cover 同时使用"`
+	ContentFormat  string   `json:"content_format,omitempty" jsonschema:"正文格式（可选）: plain 原样输入（默认）| markdown 先把 Markdown 转换为小红书的纯文本风格：标题变为 emoji 开头的行，列表变为 emoji 项目符号，去掉粗体等标记和链接，正文中的 #标签 移到 tags"`
+	TitleOverflow  string   `json:"title_overflow,omitempty" jsonschema:"标题超过20字时的处理方式（可选）: error 返回错误（默认）| truncate 截断标题 | move_to_body 截断标题并把完整标题放到正文开头"`
+	Async          bool     `json:"async,omitempty" jsonschema:"是否提交为后台任务（可选），默认false。为true时立即返回 job_id，用 get_job 查询进度和结果，避免发布耗时过长导致调用超时"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），调用超时后用同一个 key 重试时不会重复发布，直接返回第一次的结果。内容不同的请求不能复用同一个 key"`
+}
+
+// ValidateNoteArgs 校验笔记的参数
+type ValidateNoteArgs struct {
+	Title   string   `json:"title" jsonschema:"笔记标题"`
+	Content string   `json:"content" jsonschema:"正文内容，不包含标签"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选），发布时会追加到正文末尾并计入正文长度"`
+	Images  []string `json:"images,omitempty" jsonschema:"图片列表（可选），只检查数量（1-18张），不会下载"`
+	Video   string   `json:"video,omitempty" jsonschema:"视频（可选），填写时按视频笔记校验，不检查图片数量"`
+}
+
+// LintTextArgs 敏感词检查的参数
+type LintTextArgs struct {
+	Text    string   `json:"text,omitempty" jsonschema:"要检查的文本（可选），如评论内容"`
+	Title   string   `json:"title,omitempty" jsonschema:"笔记标题（可选）"`
+	Content string   `json:"content,omitempty" jsonschema:"笔记正文（可选）"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选），每个标签单独检查"`
+}
+
+// SuggestTopicsArgs 话题联想的参数
+type SuggestTopicsArgs struct {
+	Keyword string `json:"keyword" jsonschema:"话题关键词，如 美食、旅行，不需要带#"`
+}
+
+// JobIDArgs 任务操作的参数
+type JobIDArgs struct {
+	JobID string `json:"job_id" jsonschema:"任务ID，异步发布时返回的 job_id"`
+}
+
+// ListJobsArgs 列出任务的参数
+type ListJobsArgs struct {
+	Status string `json:"status,omitempty" jsonschema:"任务状态筛选（可选）: scheduled|queued|running|succeeded|failed|canceled，不填返回全部"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"最多返回的任务数（可选），默认20"`
+}
+
+// ListScheduledPostsArgs 列出定时发布的参数
+type ListScheduledPostsArgs struct {
+	Limit int `json:"limit,omitempty" jsonschema:"最多返回条数（可选），默认20"`
+}
+
+// ReschedulePostArgs 修改定时发布时间的参数
+type ReschedulePostArgs struct {
+	JobID      string `json:"job_id" jsonschema:"定时发布的任务ID，从 list_scheduled_posts 获取"`
+	ScheduleAt string `json:"schedule_at" jsonschema:"新的发布时间，ISO8601格式如 2024-01-20T10:30:00+08:00，必须晚于当前时间"`
+}
+
+// DraftIDArgs 草稿操作的参数
+type DraftIDArgs struct {
+	DraftID string `json:"draft_id" jsonschema:"草稿ID，从 list_drafts 返回结果的 id 字段获取"`
+}
+
+// ListMyNotesArgs 获取自己发布的笔记列表的参数
+type ListMyNotesArgs struct {
+	Status string `json:"status,omitempty" jsonschema:"笔记状态筛选: all|published|reviewing|rejected，默认 all"`
+	Page   int    `json:"page,omitempty" jsonschema:"页码，从0开始，默认0。返回结果中 has_more 为 true 时可继续获取下一页"`
+}
+
+// EditNoteArgs 编辑已发布笔记的参数
+type EditNoteArgs struct {
+	NoteID     string   `json:"note_id" jsonschema:"要编辑的笔记ID，从 list_my_notes 获取"`
+	Title      string   `json:"title" jsonschema:"新的标题（小红书限制：最多20个中文字或英文单词）"`
+	Content    string   `json:"content" jsonschema:"新的正文，会替换整个原正文（包括原有话题标签），不包含以#开头的标签内容"`
+	Tags       []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选），追加在新正文之后"`
+	Images     []string `json:"images,omitempty" jsonschema:"替换全部图片（可选），支持HTTP/HTTPS链接、本地绝对路径、file:// 链接、data:image/...;base64 图片或 media_id。不填则保留原图片"`
+	ImageOrder []int    `json:"image_order,omitempty" jsonschema:"调整现有图片顺序（可选），按新顺序列出原图片位置（从1开始），如 [3,1,2]。不能与 images 同时使用"`
+}
+
+// DeleteNoteArgs 删除笔记的参数
+type DeleteNoteArgs struct {
+	NoteID    string `json:"note_id" jsonschema:"要删除的笔记ID，从 list_my_notes 获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"笔记的访问令牌（可选），笔记管理中找不到笔记时用于通过笔记详情页删除"`
 }
 
 // SearchFeedsArgs 搜索内容的参数
@@ -72,18 +178,22 @@ type UserProfileArgs struct {
 
 // PostCommentArgs 发表评论的参数
 type PostCommentArgs struct {
-	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
-	Content   string `json:"content" jsonschema:"评论内容"`
+	FeedID         string   `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken      string   `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Content        string   `json:"content" jsonschema:"评论内容"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"要@的用户列表（可选），每项为昵称、用户ID或 昵称:用户ID。评论中写了 @昵称 的会在原位置插入，其余追加到末尾"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），调用超时后用同一个 key 重试时不会重复发表，直接返回第一次的结果"`
 }
 
 // ReplyCommentArgs 回复评论的参数
 type ReplyCommentArgs struct {
-	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
-	CommentID string `json:"comment_id,omitempty" jsonschema:"目标评论ID，从评论列表获取"`
-	UserID    string `json:"user_id,omitempty" jsonschema:"目标评论用户ID，从评论列表获取"`
-	Content   string `json:"content" jsonschema:"回复内容"`
+	FeedID         string   `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken      string   `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	CommentID      string   `json:"comment_id,omitempty" jsonschema:"目标评论ID，从评论列表获取"`
+	UserID         string   `json:"user_id,omitempty" jsonschema:"目标评论用户ID，从评论列表获取"`
+	Content        string   `json:"content" jsonschema:"回复内容"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"要@的用户列表（可选），每项为昵称、用户ID或 昵称:用户ID。回复中写了 @昵称 的会在原位置插入，其余追加到末尾"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），调用超时后用同一个 key 重试时不会重复发表，直接返回第一次的结果"`
 }
 
 // LikeFeedArgs 点赞参数
@@ -201,6 +311,38 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		}),
 	)
 
+	// 工具 3.1: 导入 cookies
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "import_cookies",
+			Description: "从已登录的浏览器导入小红书 cookies（支持 cookies.txt、EditThisCookie JSON、Cookie 请求头），导入后自动验证登录状态，验证不通过时恢复原 cookies",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Import Cookies",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("import_cookies", func(ctx context.Context, req *mcp.CallToolRequest, args ImportCookiesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleImportCookies(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 3.2: 导出 cookies
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_cookies",
+			Description: "导出当前保存的小红书 cookies（netscape / json / header 格式）",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Export Cookies",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("export_cookies", func(ctx context.Context, req *mcp.CallToolRequest, args ExportCookiesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleExportCookies(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
 	// 工具 4: 发布内容
 	mcp.AddTool(server,
 		&mcp.Tool{
@@ -214,17 +356,77 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		withPanicRecovery("publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
 			// 转换参数格式到现有的 handler
 			argsMap := map[string]interface{}{
-				"title":       args.Title,
-				"content":     args.Content,
-				"images":      convertStringsToInterfaces(args.Images),
-				"tags":        convertStringsToInterfaces(args.Tags),
-				"schedule_at": args.ScheduleAt,
+				"title":           args.Title,
+				"content":         args.Content,
+				"images":          convertStringsToInterfaces(args.Images),
+				"tags":            convertStringsToInterfaces(args.Tags),
+				"schedule_at":     args.ScheduleAt,
+				"draft":           args.Draft,
+				"visibility":      args.Visibility,
+				"location":        args.Location,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"image_aspect":    args.ImageAspect,
+				"image_fit":       args.ImageFit,
+				"title_overflow":  args.TitleOverflow,
+				"content_format":  args.ContentFormat,
+				"async":           args.Async,
+				"schedule_mode":   args.ScheduleMode,
+				"missed_policy":   args.MissedPolicy,
+				"idempotency_key": args.IdempotencyKey,
 			}
 			result := appServer.handlePublishContent(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
 
+	// 工具 4.1: 话题联想
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "suggest_topics",
+			Description: "获取发布编辑器中关键词的话题联想（话题名称和浏览量），用于在发布前挑选 tags，不会发布或保存笔记",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Suggest Topics",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("suggest_topics", func(ctx context.Context, req *mcp.CallToolRequest, args SuggestTopicsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleSuggestTopics(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 4.2: 校验笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "validate_note",
+			Description: "按发布编辑器的规则校验笔记（标题20字、正文含标签1000字、图片1-18张、标签最多10个），一次返回所有问题，不打开浏览器",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Validate Note",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("validate_note", func(ctx context.Context, req *mcp.CallToolRequest, args ValidateNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleValidateNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 4.3: 敏感词检查
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "lint_text",
+			Description: "按敏感词词库检查笔记或评论文本（导流、极限词、医疗功效等），返回命中位置、类别、严重程度和替换建议。发布笔记和评论时会自动检查，拦截模式下命中 high 级别的词会阻止发布",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Lint Text",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("lint_text", func(ctx context.Context, req *mcp.CallToolRequest, args LintTextArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleLintText(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
 	// 工具 5: 获取Feed列表
 	mcp.AddTool(server,
 		&mcp.Tool{
@@ -351,9 +553,11 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		},
 		withPanicRecovery("post_comment_to_feed", func(ctx context.Context, req *mcp.CallToolRequest, args PostCommentArgs) (*mcp.CallToolResult, any, error) {
 			argsMap := map[string]interface{}{
-				"feed_id":    args.FeedID,
-				"xsec_token": args.XsecToken,
-				"content":    args.Content,
+				"feed_id":         args.FeedID,
+				"xsec_token":      args.XsecToken,
+				"content":         args.Content,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"idempotency_key": args.IdempotencyKey,
 			}
 			result := appServer.handlePostComment(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -379,22 +583,24 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			}
 
 			argsMap := map[string]interface{}{
-				"feed_id":    args.FeedID,
-				"xsec_token": args.XsecToken,
-				"comment_id": args.CommentID,
-				"user_id":    args.UserID,
-				"content":    args.Content,
+				"feed_id":         args.FeedID,
+				"xsec_token":      args.XsecToken,
+				"comment_id":      args.CommentID,
+				"user_id":         args.UserID,
+				"content":         args.Content,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"idempotency_key": args.IdempotencyKey,
 			}
 			result := appServer.handleReplyComment(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
 		},
 	)
 
-	// 工具 11: 发布视频（仅本地文件）
+	// 工具 11: 发布视频（本地文件或链接）
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_with_video",
-			Description: "发布小红书视频内容（仅支持本地单个视频文件）",
+			Description: "发布小红书视频内容（单个视频，支持本地文件路径或 HTTP 链接）",
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Publish Video",
 				DestructiveHint: boolPtr(true),
@@ -402,17 +608,205 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		},
 		withPanicRecovery("publish_with_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
 			argsMap := map[string]interface{}{
-				"title":       args.Title,
-				"content":     args.Content,
-				"video":       args.Video,
-				"tags":        convertStringsToInterfaces(args.Tags),
-				"schedule_at": args.ScheduleAt,
+				"title":           args.Title,
+				"content":         args.Content,
+				"video":           args.Video,
+				"tags":            convertStringsToInterfaces(args.Tags),
+				"schedule_at":     args.ScheduleAt,
+				"draft":           args.Draft,
+				"visibility":      args.Visibility,
+				"location":        args.Location,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"cover":           args.Cover,
+				"cover_at":        args.CoverAt,
+				"title_overflow":  args.TitleOverflow,
+				"content_format":  args.ContentFormat,
+				"async":           args.Async,
+				"schedule_mode":   args.ScheduleMode,
+				"missed_policy":   args.MissedPolicy,
+				"idempotency_key": args.IdempotencyKey,
 			}
 			result := appServer.handlePublishVideo(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
 
+	// 工具 11.1: 获取草稿箱列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_drafts",
+			Description: "获取创作者中心草稿箱中的草稿列表（图文和视频），返回草稿ID、标题和更新时间",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Drafts",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_drafts", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListDrafts(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.2: 发布草稿
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_draft",
+			Description: "发布草稿箱中的指定草稿，返回笔记ID和链接",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Publish Draft",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("publish_draft", func(ctx context.Context, req *mcp.CallToolRequest, args DraftIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handlePublishDraft(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.3: 删除草稿
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_draft",
+			Description: "删除草稿箱中的指定草稿，删除后无法恢复",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Draft",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("delete_draft", func(ctx context.Context, req *mcp.CallToolRequest, args DraftIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDeleteDraft(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.4: 获取自己发布的笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_my_notes",
+			Description: "获取当前账号在创作者中心发布的笔记列表，包含发布时间、可见范围、审核状态（审核中/已发布/未通过及原因）和浏览/点赞/评论/收藏数，支持分页",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List My Notes",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_my_notes", func(ctx context.Context, req *mcp.CallToolRequest, args ListMyNotesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListMyNotes(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.5: 编辑已发布的笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "edit_note",
+			Description: "编辑当前账号已发布的笔记：替换标题、正文和话题标签，可选替换全部图片或调整图片顺序。保留笔记原有的点赞、收藏和评论",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Edit Note",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("edit_note", func(ctx context.Context, req *mcp.CallToolRequest, args EditNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleEditNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.6: 删除自己的笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_note",
+			Description: "删除当前账号发布的笔记，删除后无法恢复。删除完成后会确认笔记已从笔记管理中消失",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Note",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("delete_note", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDeleteNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.7: 查询发布任务
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_job",
+			Description: "查询异步发布任务的状态（queued/running/succeeded/failed/canceled）、当前步骤进度（如上传第3/9张图片、填写表单、提交）和发布结果",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Job",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_job", func(ctx context.Context, req *mcp.CallToolRequest, args JobIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetJob(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.8: 列出发布任务
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_jobs",
+			Description: "按提交时间倒序列出异步发布任务，可按状态筛选",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Jobs",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_jobs", func(ctx context.Context, req *mcp.CallToolRequest, args ListJobsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListJobs(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.9: 取消发布任务
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "cancel_job",
+			Description: "取消本地定时发布、等待中或执行中的异步发布任务。执行中的任务会中断当前操作，已经点击发布的笔记无法撤回",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Cancel Job",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("cancel_job", func(ctx context.Context, req *mcp.CallToolRequest, args JobIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCancelJob(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.10: 列出本地定时发布
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_scheduled_posts",
+			Description: "列出 schedule_mode=local 保存的定时发布，按发布时间先后排列，最先发布的在前",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Scheduled Posts",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_scheduled_posts", func(ctx context.Context, req *mcp.CallToolRequest, args ListScheduledPostsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListScheduledPosts(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 11.11: 修改定时发布时间
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "reschedule_post",
+			Description: "修改本地定时发布的时间，只能修改还没有开始发布的任务，可以提前也可以推迟",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Reschedule Post",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("reschedule_post", func(ctx context.Context, req *mcp.CallToolRequest, args ReschedulePostArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleReschedulePost(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
 	// 工具 12: 点赞笔记
 	mcp.AddTool(server,
 		&mcp.Tool{
@@ -455,7 +849,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 14)
+	logrus.Infof("Registered %d MCP tools", 30)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -489,6 +883,17 @@ func convertToMCPResult(result *MCPToolResult) *mcp.CallToolResult {
 	}
 }
 
+// convertInterfacesToStrings 辅助函数：将 []interface{} 中的字符串取出，忽略其他类型
+func convertInterfacesToStrings(items []interface{}) []string {
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 // convertStringsToInterfaces 辅助函数：将 []string 转换为 []interface{}
 func convertStringsToInterfaces(strs []string) []interface{} {
 	result := make([]interface{}, len(strs))
@@ -0,0 +1,207 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxInlineImageBytes 内联图片（data URI / base64）解码后的大小上限
+const MaxInlineImageBytes = 20 << 20
+
+// IsInlineImage 判断是否为内联图片：data:image/png;base64,... 或带 MIME 前缀的 base64（image/png;base64,...）
+func IsInlineImage(s string) bool {
+	_, _, ok := splitInlineImage(s)
+	return ok
+}
+
+// IsFileURL 判断是否为 file:// 链接
+func IsFileURL(s string) bool {
+	return strings.HasPrefix(strings.ToLower(s), "file://")
+}
+
+// splitInlineImage 拆出 MIME 类型和 base64 数据
+func splitInlineImage(s string) (mimeType, payload string, ok bool) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 5 && strings.EqualFold(s[:5], "data:") {
+		s = s[5:]
+	}
+
+	header, payload, found := strings.Cut(s, ",")
+	if !found {
+		return "", "", false
+	}
+	params := strings.Split(header, ";")
+	if len(params) < 2 || !strings.EqualFold(strings.TrimSpace(params[len(params)-1]), "base64") {
+		return "", "", false
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(params[0]))
+	if !strings.HasPrefix(mimeType, "image/") || strings.Count(mimeType, "/") != 1 || strings.ContainsAny(mimeType, " \\") {
+		return "", "", false
+	}
+	return mimeType, payload, true
+}
+
+// decodeInlineImage 解码内联图片，校验大小和图片格式
+func decodeInlineImage(s string) ([]byte, string, error) {
+	mimeType, payload, ok := splitInlineImage(s)
+	if !ok {
+		return nil, "", errors.New("不是有效的 data URI 或 base64 图片")
+	}
+
+	// 去掉换行等空白，兼容分行的 base64
+	payload = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, payload)
+	if payload == "" {
+		return nil, "", errors.New("base64 图片数据为空")
+	}
+	if base64.StdEncoding.DecodedLen(len(payload)) > MaxInlineImageBytes+3 {
+		return nil, "", errors.Errorf("base64 图片超过 %dMB 上限", MaxInlineImageBytes>>20)
+	}
+
+	data, err := decodeBase64(payload)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "base64 图片解码失败")
+	}
+	if len(data) > MaxInlineImageBytes {
+		return nil, "", errors.Errorf("base64 图片超过 %dMB 上限", MaxInlineImageBytes>>20)
+	}
+
+	kind, err := filetype.Match(data)
+	if err != nil || !filetype.IsImage(data) {
+		return nil, "", errors.Errorf("base64 数据不是有效的图片（声明为 %s）", mimeType)
+	}
+	return data, kind.Extension, nil
+}
+
+// decodeBase64 依次尝试标准、无填充和 URL 安全的 base64 编码
+func decodeBase64(s string) ([]byte, error) {
+	var lastErr error
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		data, err := enc.DecodeString(s)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// inlineImagePrefix 内联图片解码后的文件名前缀，清理时只删除这些文件
+const inlineImagePrefix = "inline_"
+
+// SaveInlineImage 把内联图片写入 savePath，返回文件路径，相同内容复用同一个文件。
+// 文件由 CleanupInlineImages 按修改时间清理，复用时会更新修改时间
+func SaveInlineImage(savePath, s string) (string, error) {
+	data, ext, err := decodeInlineImage(s)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create save path")
+	}
+
+	hash := sha256.Sum256(data)
+	filePath := filepath.Join(savePath, fmt.Sprintf("%s%x.%s", inlineImagePrefix, hash[:8], ext))
+	if _, err := os.Stat(filePath); err == nil {
+		now := time.Now()
+		if err := os.Chtimes(filePath, now, now); err == nil {
+			return filePath, nil
+		}
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", errors.Wrap(err, "failed to save image")
+	}
+	return filePath, nil
+}
+
+// CleanupInlineImages 删除 savePath 中超过 maxAge 没有使用的内联图片，返回删除的数量
+func CleanupInlineImages(savePath string, maxAge time.Duration) int {
+	entries, err := os.ReadDir(savePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warnf("读取图片目录失败: %v", err)
+		}
+		return 0
+	}
+
+	removed := 0
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), inlineImagePrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(savePath, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartInlineImageCleanup 定期清理过期的内联图片，直到 ctx 结束
+func StartInlineImageCleanup(ctx context.Context, savePath string, maxAge, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := CleanupInlineImages(savePath, maxAge); n > 0 {
+					logrus.Infof("已清理 %d 个过期的 base64 图片", n)
+				}
+			}
+		}
+	}()
+}
+
+// FileURLToPath 把 file:// 链接转换为本地路径，并检查文件存在
+func FileURLToPath(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Scheme, "file") {
+		return "", errors.Errorf("无效的 file 链接: %s", rawURL)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", errors.Errorf("不支持其他主机上的文件: %s", rawURL)
+	}
+
+	path := u.Path
+	// file:///C:/Users/... 在 Windows 上转换为 C:/Users/...
+	if runtime.GOOS == "windows" && len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	path = filepath.FromSlash(path)
+	if path == "" {
+		return "", errors.Errorf("无效的 file 链接: %s", rawURL)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "图片文件不存在: %s", path)
+	}
+	if stat.IsDir() {
+		return "", errors.Errorf("不是图片文件: %s", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,154 @@
+package downloader
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.Black)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestIsInlineImage(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"data:image/png;base64,iVBORw0KGgo=", true},
+		{"DATA:image/jpeg;base64,/9j/4AAQ", true},
+		{"image/webp;base64,UklGRg==", true},
+		{"data:text/plain;base64,aGVsbG8=", false},
+		{"data:image/png,rawdata", false},
+		{"/Users/user/image;base64,x.jpg", false},
+		{"https://example.com/a.png", false},
+		{"/local/path/image.jpg", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, IsInlineImage(tt.input), tt.input)
+	}
+}
+
+func TestSaveInlineImage(t *testing.T) {
+	data := pngBytes(t)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	dir := t.TempDir()
+
+	t.Run("data uri", func(t *testing.T) {
+		path, err := SaveInlineImage(dir, "data:image/png;base64,"+encoded)
+		require.NoError(t, err)
+		assert.Equal(t, ".png", filepath.Ext(path))
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("bare base64 with mime hint and line breaks", func(t *testing.T) {
+		wrapped := encoded[:10] + "\n" + encoded[10:]
+		path, err := SaveInlineImage(dir, "image/png;base64,"+wrapped)
+		require.NoError(t, err)
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("url-safe without padding", func(t *testing.T) {
+		_, err := SaveInlineImage(dir, "image/png;base64,"+base64.RawURLEncoding.EncodeToString(data))
+		assert.NoError(t, err)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := SaveInlineImage(dir, "data:image/png;base64,"+base64.StdEncoding.EncodeToString([]byte("hello world")))
+		assert.ErrorContains(t, err, "不是有效的图片")
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		_, err := SaveInlineImage(dir, "data:image/png;base64,@@@@")
+		assert.ErrorContains(t, err, "解码失败")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		huge := strings.Repeat("A", MaxInlineImageBytes/3*4+64)
+		_, err := SaveInlineImage(dir, "data:image/png;base64,"+huge)
+		assert.ErrorContains(t, err, "上限")
+	})
+}
+
+func TestCleanupInlineImages(t *testing.T) {
+	dir := t.TempDir()
+	encoded := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(t))
+
+	path, err := SaveInlineImage(dir, encoded)
+	require.NoError(t, err)
+	other := filepath.Join(dir, "other.png")
+	require.NoError(t, os.WriteFile(other, pngBytes(t), 0644))
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	require.NoError(t, os.Chtimes(other, old, old))
+
+	// 复用时更新修改时间，不会被清理
+	_, err = SaveInlineImage(dir, encoded)
+	require.NoError(t, err)
+	assert.Equal(t, 0, CleanupInlineImages(dir, time.Hour))
+
+	require.NoError(t, os.Chtimes(path, old, old))
+	assert.Equal(t, 1, CleanupInlineImages(dir, time.Hour))
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, other)
+}
+
+func TestFileURLToPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a b.png")
+	require.NoError(t, os.WriteFile(file, pngBytes(t), 0644))
+
+	path, err := FileURLToPath("file://" + filepath.ToSlash(strings.ReplaceAll(file, " ", "%20")))
+	require.NoError(t, err)
+	assert.Equal(t, file, path)
+
+	_, err = FileURLToPath("file://" + filepath.ToSlash(filepath.Join(dir, "missing.png")))
+	assert.ErrorContains(t, err, "不存在")
+
+	_, err = FileURLToPath("file://otherhost/tmp/a.png")
+	assert.ErrorContains(t, err, "其他主机")
+}
+
+func TestProcessImages_MixedInputs(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.png")
+	require.NoError(t, os.WriteFile(local, pngBytes(t), 0644))
+
+	p := &ImageProcessor{downloader: NewImageDownloader(dir)}
+	paths, err := p.ProcessImages([]string{
+		local,
+		"file://" + filepath.ToSlash(local),
+		"data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(t)),
+	})
+	require.NoError(t, err)
+	require.Len(t, paths, 3)
+	assert.Equal(t, local, paths[0])
+	assert.Equal(t, local, paths[1])
+	assert.Equal(t, dir, filepath.Dir(paths[2]))
+
+	_, err = p.ProcessImages([]string{"data:image/png;base64,aGVsbG8="})
+	assert.ErrorContains(t, err, "第1张图片")
+	assert.NotContains(t, err.Error(), "aGVsbG8=")
+}
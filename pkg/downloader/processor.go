@@ -19,23 +19,39 @@ func NewImageProcessor() *ImageProcessor {
 }
 
 // ProcessImages 处理图片列表，返回本地文件路径
-// 支持两种输入格式：
+// 支持以下输入格式：
 // 1. URL格式 (http/https开头) - 自动下载到本地
-// 2. 本地文件路径 - 直接使用
-// 保持原始图片顺序，如果下载失败直接返回错误
+// 2. data URI (data:image/png;base64,...) 或带 MIME 前缀的 base64 (image/png;base64,...) - 解码后保存到本地
+// 3. file:// 链接 - 转换为本地路径
+// 4. 本地文件路径 - 直接使用
+// 保持原始图片顺序，如果处理失败直接返回错误
 func (p *ImageProcessor) ProcessImages(images []string) ([]string, error) {
 	localPaths := make([]string, 0, len(images))
 
 	// 按顺序处理每张图片
-	for _, image := range images {
-		if IsImageURL(image) {
+	for i, image := range images {
+		switch {
+		case IsImageURL(image):
 			// URL图片：立即下载，失败直接返回错误
 			localPath, err := p.downloader.DownloadImage(image)
 			if err != nil {
 				return nil, fmt.Errorf("下载图片失败 %s: %w", image, err)
 			}
 			localPaths = append(localPaths, localPath)
-		} else {
+		case IsInlineImage(image):
+			// 内联图片：错误信息中不带原始数据，只标明是第几张
+			localPath, err := SaveInlineImage(p.downloader.savePath, image)
+			if err != nil {
+				return nil, fmt.Errorf("第%d张图片处理失败: %w", i+1, err)
+			}
+			localPaths = append(localPaths, localPath)
+		case IsFileURL(image):
+			localPath, err := FileURLToPath(image)
+			if err != nil {
+				return nil, err
+			}
+			localPaths = append(localPaths, localPath)
+		default:
 			// 本地路径直接使用
 			localPaths = append(localPaths, image)
 		}
@@ -0,0 +1,304 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// videoDownloadAttempts 下载中断后续传的最大尝试次数
+const videoDownloadAttempts = 3
+
+// videoExtensions 允许的视频格式（按文件头识别）
+var videoExtensions = map[string]bool{
+	"mp4": true,
+	"mov": true,
+	"m4v": true,
+}
+
+// partLocks 按 .part 文件加锁。.part 文件名只由 URL 决定，以便中断后续传，
+// 同一 URL 的并发下载需要依次进行，否则会同时写同一个文件
+var partLocks = struct {
+	sync.Mutex
+	m map[string]*partLock
+}{m: make(map[string]*partLock)}
+
+type partLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockPart 锁住 path，返回解锁函数，没有人等待时释放对应的锁
+func lockPart(path string) func() {
+	partLocks.Lock()
+	l := partLocks.m[path]
+	if l == nil {
+		l = &partLock{}
+		partLocks.m[path] = l
+	}
+	l.refs++
+	partLocks.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		partLocks.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(partLocks.m, path)
+		}
+		partLocks.Unlock()
+	}
+}
+
+// VideoDownloader 视频下载器，支持断点续传
+type VideoDownloader struct {
+	savePath   string
+	maxBytes   int64
+	httpClient *http.Client
+}
+
+// NewVideoDownloader 创建视频下载器，maxBytes 为 0 表示不限制大小
+func NewVideoDownloader(savePath string, maxBytes int64) *VideoDownloader {
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		panic(fmt.Sprintf("failed to create save path: %v", err))
+	}
+
+	return &VideoDownloader{
+		savePath: savePath,
+		maxBytes: maxBytes,
+		httpClient: &http.Client{
+			// 视频较大，不限制整体耗时，只限制建立连接和等待响应头
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				TLSHandshakeTimeout:   15 * time.Second,
+				ResponseHeaderTimeout: 30 * time.Second,
+			},
+		},
+	}
+}
+
+// DownloadVideo 下载视频到本地，返回文件路径。
+// 下载中的数据写入 .part 文件，中断后再次下载同一 URL 时用 Range 请求续传；同一 URL 同时只有一个下载。
+func (d *VideoDownloader) DownloadVideo(ctx context.Context, videoURL string) (string, error) {
+	if !IsVideoURL(videoURL) {
+		return "", errors.New("invalid video URL format")
+	}
+	if u, err := url.Parse(videoURL); err != nil || u.Host == "" {
+		return "", errors.New("invalid video URL format")
+	}
+
+	partPath := filepath.Join(d.savePath, d.baseName(videoURL)+".part")
+	unlock := lockPart(partPath)
+	defer unlock()
+
+	for attempt := 1; ; attempt++ {
+		err := d.fetch(ctx, videoURL, partPath)
+		if err == nil {
+			break
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) || ctx.Err() != nil {
+			return "", err
+		}
+		if attempt == videoDownloadAttempts {
+			return "", errors.Wrap(err, "下载视频失败")
+		}
+		logrus.Warnf("下载视频中断（第 %d 次），稍后续传: %v", attempt, err)
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+
+	kind, err := filetype.MatchFile(partPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to detect file type")
+	}
+	if !videoExtensions[kind.Extension] {
+		os.Remove(partPath)
+		return "", errors.Errorf("下载的文件不是 MP4/MOV 视频（识别为 %s）", describeKind(kind.Extension))
+	}
+
+	filePath := filepath.Join(d.savePath, d.baseName(videoURL)+"."+kind.Extension)
+	if err := os.Rename(partPath, filePath); err != nil {
+		return "", errors.Wrap(err, "failed to save video")
+	}
+	return filePath, nil
+}
+
+// permanentError 重试也无法解决的下载错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func permanent(format string, args ...any) error {
+	return &permanentError{err: fmt.Errorf(format, args...)}
+}
+
+// fetch 从 .part 文件当前大小处继续下载，返回 nil 表示下载完整
+func (d *VideoDownloader) fetch(ctx context.Context, videoURL, partPath string) error {
+	var offset int64
+	if stat, err := os.Stat(partPath); err == nil {
+		offset = stat.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, videoURL, nil)
+	if err != nil {
+		return permanent("failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download video from %s", videoURL)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	var total int64 = -1
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// 服务器不支持 Range，从头下载
+		flags |= os.O_TRUNC
+		offset = 0
+		total = resp.ContentLength
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return permanent("服务器返回的续传范围不正确: %s", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+		total = size
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 已经下载完整
+		if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size == offset {
+			return nil
+		}
+		os.Remove(partPath)
+		return errors.New("续传范围无效，重新下载")
+	default:
+		if resp.StatusCode >= 500 {
+			return errors.Errorf("download failed with status %d", resp.StatusCode)
+		}
+		return permanent("download failed with status %d for URL: %s", resp.StatusCode, videoURL)
+	}
+
+	if err := checkVideoContentType(resp.Header.Get("Content-Type")); err != nil {
+		return &permanentError{err: err}
+	}
+	if d.maxBytes > 0 && total > d.maxBytes {
+		return permanent("视频大小 %dMB 超过上限 %dMB", total>>20, d.maxBytes>>20)
+	}
+
+	f, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return permanent("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	body := io.Reader(resp.Body)
+	if d.maxBytes > 0 {
+		body = io.LimitReader(resp.Body, d.maxBytes-offset+1)
+	}
+	written, err := io.Copy(f, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read video data")
+	}
+	if d.maxBytes > 0 && offset+written > d.maxBytes {
+		f.Close()
+		os.Remove(partPath)
+		return permanent("视频大小超过上限 %dMB", d.maxBytes>>20)
+	}
+	if total >= 0 && offset+written < total {
+		return errors.Errorf("下载不完整: %d/%d 字节", offset+written, total)
+	}
+	return nil
+}
+
+// baseName 按 URL 生成固定的文件名，同一 URL 可以续传
+func (d *VideoDownloader) baseName(videoURL string) string {
+	hash := sha256.Sum256([]byte(videoURL))
+	return fmt.Sprintf("video_%x", hash[:8])
+}
+
+// checkVideoContentType 拒绝明显不是视频的响应，如网页或图片
+func checkVideoContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "video/"),
+		mediaType == "application/octet-stream",
+		mediaType == "binary/octet-stream",
+		mediaType == "application/mp4":
+		return nil
+	}
+	return errors.Errorf("链接返回的内容类型为 %s，不是视频", mediaType)
+}
+
+// parseContentRange 解析 "bytes 100-199/1000" 或 "bytes */1000"，返回起始位置和总大小
+func parseContentRange(value string) (start, total int64, ok bool) {
+	value, found := strings.CutPrefix(strings.TrimSpace(value), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rangePart, totalPart, found := strings.Cut(value, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	total = -1
+	if totalPart != "*" {
+		if total, ok = parseInt(totalPart); !ok {
+			return 0, 0, false
+		}
+	}
+	if rangePart == "*" {
+		return 0, total, true
+	}
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	if start, ok = parseInt(startPart); !ok {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+func parseInt(s string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return n, err == nil && n >= 0
+}
+
+func describeKind(ext string) string {
+	if ext == "" || ext == filetype.Unknown.Extension {
+		return "未知格式"
+	}
+	return ext
+}
+
+// IsVideoURL 判断字符串是否为视频URL
+func IsVideoURL(path string) bool {
+	return IsImageURL(path)
+}
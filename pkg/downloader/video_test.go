@@ -0,0 +1,161 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMP4 生成带 ftyp 头的 MP4 数据
+func fakeMP4(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"))
+	for i := 24; i < size; i++ {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func serveVideo(t *testing.T, data []byte, contentType string, ranges *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ranges != nil {
+			*ranges = append(*ranges, r.Header.Get("Range"))
+		}
+		w.Header().Set("Content-Type", contentType)
+		http.ServeContent(w, r, "video.mp4", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownloadVideo(t *testing.T) {
+	data := fakeMP4(64 * 1024)
+	srv := serveVideo(t, data, "video/mp4", nil)
+
+	d := NewVideoDownloader(t.TempDir(), 0)
+	path, err := d.DownloadVideo(context.Background(), srv.URL+"/a.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, ".mp4", filepath.Ext(path))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	_, err = os.Stat(strings.TrimSuffix(path, ".mp4") + ".part")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadVideo_ConcurrentSameURL(t *testing.T) {
+	data := fakeMP4(256 * 1024)
+	srv := serveVideo(t, data, "video/mp4", nil)
+	d := NewVideoDownloader(t.TempDir(), 0)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var path string
+			path, errs[i] = d.DownloadVideo(context.Background(), srv.URL+"/same.mp4")
+			if errs[i] == nil {
+				got, err := os.ReadFile(path)
+				if err == nil && !bytes.Equal(data, got) {
+					err = assert.AnError
+				}
+				errs[i] = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, partLocks.m)
+}
+
+func TestDownloadVideo_Resume(t *testing.T) {
+	data := fakeMP4(64 * 1024)
+	var ranges []string
+	srv := serveVideo(t, data, "video/mp4", &ranges)
+
+	dir := t.TempDir()
+	d := NewVideoDownloader(dir, 0)
+	videoURL := srv.URL + "/b.mp4"
+
+	// 模拟上次下载到一半中断
+	partPath := filepath.Join(dir, d.baseName(videoURL)+".part")
+	require.NoError(t, os.WriteFile(partPath, data[:10000], 0644))
+
+	path, err := d.DownloadVideo(context.Background(), videoURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bytes=10000-"}, ranges)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestDownloadVideo_Rejects(t *testing.T) {
+	t.Run("too large", func(t *testing.T) {
+		srv := serveVideo(t, fakeMP4(4096), "video/mp4", nil)
+		_, err := NewVideoDownloader(t.TempDir(), 1024).DownloadVideo(context.Background(), srv.URL)
+		assert.ErrorContains(t, err, "超过上限")
+	})
+
+	t.Run("html page", func(t *testing.T) {
+		srv := serveVideo(t, []byte("<html></html>"), "text/html; charset=utf-8", nil)
+		_, err := NewVideoDownloader(t.TempDir(), 0).DownloadVideo(context.Background(), srv.URL)
+		assert.ErrorContains(t, err, "text/html")
+	})
+
+	t.Run("not mp4", func(t *testing.T) {
+		srv := serveVideo(t, []byte("\x89PNG\r\n\x1a\n0000000000000000"), "application/octet-stream", nil)
+		_, err := NewVideoDownloader(t.TempDir(), 0).DownloadVideo(context.Background(), srv.URL)
+		assert.ErrorContains(t, err, "不是 MP4/MOV")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+		_, err := NewVideoDownloader(t.TempDir(), 0).DownloadVideo(context.Background(), srv.URL)
+		assert.ErrorContains(t, err, "404")
+	})
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		input     string
+		start     int64
+		total     int64
+		wantValid bool
+	}{
+		{"bytes 100-199/1000", 100, 1000, true},
+		{"bytes 0-0/*", 0, -1, true},
+		{"bytes */1000", 0, 1000, true},
+		{"items 0-1/2", 0, 0, false},
+		{"bytes 100-199", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.input)
+		assert.Equal(t, tt.wantValid, ok, tt.input)
+		if tt.wantValid {
+			assert.Equal(t, tt.start, start, tt.input)
+			assert.Equal(t, tt.total, total, tt.input)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package idempotency 记录带 idempotency_key 的请求和执行结果。
+// 客户端超时后用同一个 key 重试时直接返回第一次的结果，不会重复发布；第一次请求还在执行时拒绝重复的请求。
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 记录状态
+const (
+	statusRunning     = "running"
+	statusSucceeded   = "succeeded"
+	statusInterrupted = "interrupted"
+)
+
+// MaxKeyLength idempotency_key 的最大长度
+const MaxKeyLength = 255
+
+var (
+	// ErrInProgress 相同 key 的请求正在执行
+	ErrInProgress = errors.New("相同 idempotency_key 的请求正在执行，请等待完成后再重试")
+	// ErrKeyReused 相同 key 已用于内容不同的请求
+	ErrKeyReused = errors.New("idempotency_key 已用于内容不同的请求，请换一个 key")
+	// ErrInterrupted 相同 key 的请求没有正常结束（服务重启，或已提交但没有等到结果），无法确定是否已经完成
+	ErrInterrupted = errors.New("相同 idempotency_key 的请求没有正常结束（服务重启或已提交但无法确认结果），无法确认是否已完成。请先确认结果，需要重新执行时换一个 key")
+)
+
+// unconfirmedError 操作可能已经生效之后发生的错误
+type unconfirmedError struct {
+	err error
+}
+
+func (e *unconfirmedError) Error() string { return e.err.Error() }
+func (e *unconfirmedError) Unwrap() error { return e.err }
+
+// Unconfirmed 标记 fn 的错误发生在操作可能已经生效之后（如已经点击了发布但没有等到结果）。
+// 这样的失败不删除记录，而是标记为中断，再次使用这个 key 时返回 ErrInterrupted，避免重试时重复执行
+func Unconfirmed(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &unconfirmedError{err: err}
+}
+
+// record 一个 key 的执行记录
+type record struct {
+	Scope       string          `json:"scope"`
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	Status      string          `json:"status"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// Store 保存执行记录，记录超过有效期后删除
+type Store struct {
+	mu      sync.Mutex
+	dir     string
+	ttl     time.Duration
+	records map[string]*record
+	now     func() time.Time
+}
+
+// Open 打开记录目录并加载未过期的记录。上次退出时还在执行的记录标记为中断，
+// 因为无法确定是否已经发布，再次使用这个 key 时返回 ErrInterrupted
+func Open(dir string, ttl time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "创建 idempotency 记录目录失败")
+	}
+
+	s := &Store{
+		dir:     dir,
+		ttl:     ttl,
+		records: make(map[string]*record),
+		now:     time.Now,
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "读取 idempotency 记录目录失败")
+	}
+	now := s.now()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Warnf("读取 idempotency 记录失败: %s %v", file, err)
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil || !now.Before(rec.ExpiresAt) {
+			os.Remove(file)
+			continue
+		}
+		id := recordID(rec.Scope, rec.Key)
+		if rec.Status == statusRunning {
+			rec.Status = statusInterrupted
+			if err := s.save(id, &rec); err != nil {
+				logrus.Warnf("更新中断的 idempotency 记录失败: %v", err)
+			}
+		}
+		s.records[id] = &rec
+	}
+	return s, nil
+}
+
+// CheckKey 校验 idempotency_key，空字符串表示不使用
+func CheckKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	if strings.TrimSpace(key) != key {
+		return errors.New("idempotency_key 首尾不能有空白字符")
+	}
+	if utf8.RuneCountInString(key) > MaxKeyLength {
+		return errors.Errorf("idempotency_key 不能超过 %d 个字符", MaxKeyLength)
+	}
+	return nil
+}
+
+// Fingerprint 计算请求内容的摘要，用于发现同一个 key 被用在不同的请求上
+func Fingerprint(request any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", errors.Wrap(err, "序列化请求失败")
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Do 执行 fn。key 为空或 s 为 nil 时直接执行；同一 scope 下 key 已经成功执行过时返回保存的结果，
+// replayed 为 true。fn 失败时不保存记录，可以用同一个 key 重试；失败的错误由 Unconfirmed 标记时，
+// 或 fn 发生 panic 时记录为中断
+func Do[T any](s *Store, scope, key, fingerprint string, fn func() (T, error)) (result T, replayed bool, err error) {
+	if s == nil || key == "" {
+		result, err = fn()
+		return result, false, err
+	}
+	if err := CheckKey(key); err != nil {
+		return result, false, err
+	}
+
+	id := recordID(scope, key)
+	rec, err := s.begin(id, scope, key, fingerprint)
+	if err != nil {
+		return result, false, err
+	}
+	if rec != nil {
+		if err := json.Unmarshal(rec.Result, &result); err != nil {
+			return result, false, errors.Wrap(err, "读取保存的结果失败")
+		}
+		logrus.Infof("idempotency_key 重复，返回保存的结果: %s %s", scope, key)
+		return result, true, nil
+	}
+
+	// fn 中的 rod Must* 调用出错时会 panic，上层恢复后进程继续运行，
+	// 记录必须结束，否则一直是执行中，之后用这个 key 重试都会返回 ErrInProgress
+	defer func() {
+		if p := recover(); p != nil {
+			s.finish(id, result, Unconfirmed(errors.Errorf("执行时发生 panic: %v", p)))
+			panic(p)
+		}
+	}()
+
+	result, err = fn()
+	s.finish(id, result, err)
+	return result, false, err
+}
+
+// begin 检查 key 的记录：已成功时返回记录；没有记录时保存执行中的记录并返回 nil
+func (s *Store) begin(id, scope, key, fingerprint string) (*record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	if rec, ok := s.records[id]; ok {
+		if rec.Fingerprint != fingerprint {
+			return nil, ErrKeyReused
+		}
+		switch rec.Status {
+		case statusRunning:
+			return nil, ErrInProgress
+		case statusInterrupted:
+			return nil, ErrInterrupted
+		}
+		return rec, nil
+	}
+
+	rec := &record{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      statusRunning,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+	if err := s.save(id, rec); err != nil {
+		return nil, err
+	}
+	s.records[id] = rec
+	return nil, nil
+}
+
+// finish 成功时保存结果；失败时删除记录，无法确认是否已经生效的失败记录为中断
+func (s *Store) finish(id string, result any, runErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return
+	}
+	var unconfirmed *unconfirmedError
+	if errors.As(runErr, &unconfirmed) {
+		rec.Status = statusInterrupted
+		if err := s.save(id, rec); err != nil {
+			logrus.Warnf("保存 idempotency 记录失败: %v", err)
+		}
+		return
+	}
+	if runErr != nil {
+		s.remove(id)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		logrus.Warnf("序列化 idempotency 结果失败: %v", err)
+		s.remove(id)
+		return
+	}
+	rec.Status = statusSucceeded
+	rec.Result = data
+	if err := s.save(id, rec); err != nil {
+		logrus.Warnf("保存 idempotency 记录失败: %v", err)
+	}
+}
+
+// prune 删除过期的记录，调用方需要持有锁
+func (s *Store) prune(now time.Time) {
+	for id, rec := range s.records {
+		if rec.Status != statusRunning && !now.Before(rec.ExpiresAt) {
+			s.remove(id)
+		}
+	}
+}
+
+// remove 删除记录，调用方需要持有锁
+func (s *Store) remove(id string) {
+	delete(s.records, id)
+	os.Remove(filepath.Join(s.dir, id+".json"))
+}
+
+// save 写入记录文件，调用方需要持有锁
+func (s *Store) save(id string, rec *record) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "序列化 idempotency 记录失败")
+	}
+
+	path := filepath.Join(s.dir, id+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrap(err, "保存 idempotency 记录失败")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "保存 idempotency 记录失败")
+	}
+	return nil
+}
+
+// recordID 记录文件名，key 由客户端提供，不能直接作为文件名
+func recordID(scope, key string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + key))
+	return hex.EncodeToString(sum[:16])
+}
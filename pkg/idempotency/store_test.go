@@ -0,0 +1,189 @@
+package idempotency
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResult struct {
+	PostID string `json:"post_id"`
+}
+
+func TestDoReplaysResult(t *testing.T) {
+	s, err := Open(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	calls := 0
+	publish := func() (*testResult, error) {
+		calls++
+		return &testResult{PostID: "abc"}, nil
+	}
+
+	first, replayed, err := Do(s, "publish", "k1", "fp", publish)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, "abc", first.PostID)
+
+	again, replayed, err := Do(s, "publish", "k1", "fp", publish)
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, first, again)
+	assert.Equal(t, 1, calls)
+
+	// 不同 scope 的同名 key 互不影响
+	_, replayed, err = Do(s, "comment", "k1", "fp", publish)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, 2, calls)
+
+	_, _, err = Do(s, "publish", "k1", "other", publish)
+	assert.ErrorIs(t, err, ErrKeyReused)
+}
+
+func TestDoWithoutKey(t *testing.T) {
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	s, err := Open(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	Do(s, "publish", "", "fp", fn)
+	Do(s, "publish", "", "fp", fn)
+	Do(nil, "publish", "k", "fp", fn)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDoFailureIsNotRemembered(t *testing.T) {
+	s, err := Open(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	_, _, err = Do(s, "publish", "k", "fp", func() (string, error) { return "", errors.New("上传失败") })
+	assert.EqualError(t, err, "上传失败")
+
+	result, replayed, err := Do(s, "publish", "k", "fp", func() (string, error) { return "ok", nil })
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, "ok", result)
+}
+
+func TestDoUnconfirmedFailureIsInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, time.Hour)
+	require.NoError(t, err)
+
+	timeout := errors.New("等待发布结果超时")
+	_, _, err = Do(s, "publish", "k", "fp", func() (string, error) { return "", Unconfirmed(timeout) })
+	assert.ErrorIs(t, err, timeout)
+
+	calls := 0
+	_, _, err = Do(s, "publish", "k", "fp", func() (string, error) {
+		calls++
+		return "dup", nil
+	})
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Equal(t, 0, calls)
+
+	// 重启后仍然是中断
+	reopened, err := Open(dir, time.Hour)
+	require.NoError(t, err)
+	_, _, err = Do(reopened, "publish", "k", "fp", func() (string, error) { return "dup", nil })
+	assert.ErrorIs(t, err, ErrInterrupted)
+}
+
+func TestDoPanicIsInterrupted(t *testing.T) {
+	s, err := Open(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, "element not found", func() {
+		Do(s, "publish", "k", "fp", func() (string, error) { panic("element not found") })
+	})
+
+	// 记录不能停留在执行中，重试时返回中断而不是 ErrInProgress
+	calls := 0
+	_, _, err = Do(s, "publish", "k", "fp", func() (string, error) {
+		calls++
+		return "dup", nil
+	})
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Equal(t, 0, calls)
+}
+
+func TestDoRejectsConcurrentDuplicate(t *testing.T) {
+	s, err := Open(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, _, err := Do(s, "publish", "k", "fp", func() (string, error) {
+			close(started)
+			<-release
+			return "ok", nil
+		})
+		done <- err
+	}()
+	<-started
+
+	_, _, err = Do(s, "publish", "k", "fp", func() (string, error) { return "dup", nil })
+	assert.ErrorIs(t, err, ErrInProgress)
+
+	close(release)
+	require.NoError(t, <-done)
+	result, replayed, err := Do(s, "publish", "k", "fp", func() (string, error) { return "dup", nil })
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, "ok", result)
+}
+
+func TestExpiryAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	_, _, err = Do(s, "publish", "done", "fp", func() (string, error) { return "ok", nil })
+	require.NoError(t, err)
+	// 模拟执行中服务退出
+	_, err = s.begin(recordID("publish", "running"), "publish", "running", "fp")
+	require.NoError(t, err)
+
+	reopened, err := Open(dir, time.Hour)
+	require.NoError(t, err)
+	result, replayed, err := Do(reopened, "publish", "done", "fp", func() (string, error) { return "again", nil })
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, "ok", result)
+
+	_, _, err = Do(reopened, "publish", "running", "fp", func() (string, error) { return "again", nil })
+	assert.ErrorIs(t, err, ErrInterrupted)
+
+	// 过期后 key 可以重新使用
+	reopened.now = func() time.Time { return now.Add(2 * time.Hour) }
+	result, replayed, err = Do(reopened, "publish", "done", "fp", func() (string, error) { return "again", nil })
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, "again", result)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.Len(t, files, 1, "过期的记录应删除")
+	_, err = os.Stat(files[0])
+	require.NoError(t, err)
+}
+
+func TestCheckKey(t *testing.T) {
+	assert.NoError(t, CheckKey(""))
+	assert.NoError(t, CheckKey("retry-7f3a"))
+	assert.Error(t, CheckKey(" k"))
+	assert.Error(t, CheckKey(strings.Repeat("k", MaxKeyLength+1)))
+}
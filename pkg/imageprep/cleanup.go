@@ -0,0 +1,56 @@
+package imageprep
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CleanupOutputs 删除输出目录中超过 maxAge 没有更新的预处理图片，返回删除的数量。
+// 同一张图片再次处理时会覆盖输出文件并更新修改时间，正在使用的图片不会被删除
+func CleanupOutputs(dir string, maxAge time.Duration) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warnf("读取预处理图片目录失败: %v", err)
+		}
+		return 0
+	}
+
+	removed := 0
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartOutputCleanup 定期清理过期的预处理图片，直到 ctx 结束
+func StartOutputCleanup(ctx context.Context, dir string, maxAge, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := CleanupOutputs(dir, maxAge); n > 0 {
+					logrus.Infof("已清理 %d 个过期的预处理图片", n)
+				}
+			}
+		}
+	}()
+}
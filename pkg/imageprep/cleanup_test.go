@@ -0,0 +1,36 @@
+package imageprep
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupOutputs(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "photo.png")
+	writePNG(t, src, 400, 300)
+	outDir := t.TempDir()
+
+	report, err := Process(src, Options{OutputDir: outDir})
+	require.NoError(t, err)
+	require.NotEqual(t, src, report.Output)
+
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(report.Output, old, old))
+
+	// 再次处理同一张图片会覆盖输出并更新修改时间，不会被清理
+	_, err = Process(src, Options{OutputDir: outDir})
+	require.NoError(t, err)
+	assert.Equal(t, 0, CleanupOutputs(outDir, time.Hour))
+
+	require.NoError(t, os.Chtimes(report.Output, old, old))
+	assert.Equal(t, 1, CleanupOutputs(outDir, time.Hour))
+	assert.NoFileExists(t, report.Output)
+	assert.FileExists(t, src)
+
+	assert.Equal(t, 0, CleanupOutputs(filepath.Join(outDir, "missing"), time.Hour))
+}
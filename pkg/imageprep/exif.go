@@ -0,0 +1,133 @@
+package imageprep
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// hasJPEGMetadata 判断 JPEG 是否包含 EXIF/XMP 等 APP1 元数据段
+func hasJPEGMetadata(data []byte) bool {
+	found := false
+	walkJPEGSegments(data, func(marker byte, payload []byte) bool {
+		if marker == 0xE1 {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// jpegOrientation 读取 EXIF 中的方向（1~8），没有时返回 1
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	walkJPEGSegments(data, func(marker byte, payload []byte) bool {
+		if marker != 0xE1 || len(payload) < 14 || string(payload[:6]) != "Exif\x00\x00" {
+			return true
+		}
+		if o := tiffOrientation(payload[6:]); o > 0 {
+			orientation = o
+		}
+		return false
+	})
+	return orientation
+}
+
+// walkJPEGSegments 遍历 JPEG 头部的标记段，遇到图像数据（SOS）时停止
+func walkJPEGSegments(data []byte, fn func(marker byte, payload []byte) bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return
+		}
+		marker := data[i+1]
+		if marker == 0xD9 || marker == 0xDA {
+			return
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return
+		}
+		if !fn(marker, data[i+4:i+2+length]) {
+			return
+		}
+		i += 2 + length
+	}
+}
+
+// tiffOrientation 在 TIFF 结构的第一个 IFD 中查找 Orientation(0x0112) 标签
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			v := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// applyOrientation 按 EXIF 方向把图片转正
+func applyOrientation(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// 5~8 需要交换宽高
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
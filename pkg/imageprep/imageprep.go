@@ -0,0 +1,373 @@
+// Package imageprep 在上传到小红书之前预处理图片：
+// 识别格式（包括 iPhone 拍摄的 HEIC/HEIF）、统一转换为 JPEG、按最长边和文件大小缩小、调整到小红书支持的宽高比，并去掉 EXIF/GPS 等元数据。
+package imageprep
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/gen2brain/heic"
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// 调整宽高比的方式
+const (
+	FitPad  = "pad"  // 用白色填充到目标比例，不丢失画面
+	FitCrop = "crop" // 居中裁剪到目标比例
+)
+
+// 小红书支持的宽高比
+var supportedAspects = map[string][2]int{
+	"3:4": {3, 4},
+	"1:1": {1, 1},
+	"4:3": {4, 3},
+}
+
+const (
+	defaultQuality = 90
+	minQuality     = 60
+)
+
+// Options 预处理选项
+type Options struct {
+	MaxEdge   int    // 最长边像素，0 表示不限制
+	MaxBytes  int64  // 输出文件最大字节数，0 表示不限制
+	Aspect    string // 目标宽高比：3:4、1:1、4:3，为空不调整
+	Fit       string // pad | crop，默认 pad
+	Quality   int    // JPEG 质量，默认 90
+	OutputDir string // 处理后图片的保存目录
+}
+
+// Report 一张图片的预处理结果
+type Report struct {
+	Source    string   `json:"source"`
+	Output    string   `json:"output"`
+	Format    string   `json:"format"`
+	Width     int      `json:"width"`
+	Height    int      `json:"height"`
+	Bytes     int64    `json:"bytes"`
+	OutWidth  int      `json:"out_width"`
+	OutHeight int      `json:"out_height"`
+	OutBytes  int64    `json:"out_bytes"`
+	Changes   []string `json:"changes,omitempty"` // 为空表示原图直接上传
+}
+
+// ValidateOptions 检查宽高比和调整方式
+func ValidateOptions(aspect, fit string) error {
+	if aspect != "" {
+		if _, ok := supportedAspects[aspect]; !ok {
+			return errors.Errorf("不支持的宽高比: %s（支持 3:4、1:1、4:3）", aspect)
+		}
+	}
+	if fit != "" && fit != FitPad && fit != FitCrop {
+		return errors.Errorf("不支持的调整方式: %s（支持 pad、crop）", fit)
+	}
+	return nil
+}
+
+// decodeImage 解码图片。HEIC/HEIF 的文件头有 heic、mif1、heix 等多种品牌，
+// image.Decode 只能识别其中一部分，所以按识别出的类型直接用 heic 解码（libheif 编译为 WASM，不需要 cgo）
+func decodeImage(data []byte, extension, subtype string) (image.Image, string, error) {
+	if extension == "heif" || subtype == "heic" || subtype == "heif" {
+		img, err := heic.Decode(bytes.NewReader(data))
+		return img, "heic", err
+	}
+	return image.Decode(bytes.NewReader(data))
+}
+
+// ProcessAll 按顺序预处理多张图片，返回处理后的路径和每张图片的报告
+func ProcessAll(paths []string, opts Options) ([]string, []Report, error) {
+	outputs := make([]string, 0, len(paths))
+	reports := make([]Report, 0, len(paths))
+	for _, path := range paths {
+		report, err := Process(path, opts)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "预处理图片失败 %s", path)
+		}
+		outputs = append(outputs, report.Output)
+		reports = append(reports, *report)
+	}
+	return outputs, reports, nil
+}
+
+// Process 预处理一张图片。已经是无元数据的 JPEG 且不需要调整时直接使用原文件。
+func Process(path string, opts Options) (*Report, error) {
+	if err := ValidateOptions(opts.Aspect, opts.Fit); err != nil {
+		return nil, err
+	}
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = defaultQuality
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取图片失败")
+	}
+
+	kind, err := filetype.Match(data)
+	if err != nil || kind == filetype.Unknown {
+		return nil, errors.New("无法识别的文件格式")
+	}
+	if !filetype.IsImage(data) {
+		return nil, errors.Errorf("不是图片文件: %s", kind.MIME.Value)
+	}
+
+	img, format, err := decodeImage(data, kind.Extension, kind.MIME.Subtype)
+	if err != nil {
+		return nil, errors.Wrapf(err, "解码 %s 图片失败", kind.Extension)
+	}
+
+	report := &Report{
+		Source: path,
+		Format: format,
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+		Bytes:  int64(len(data)),
+	}
+
+	var changes []string
+	if format != "jpeg" {
+		changes = append(changes, fmt.Sprintf("%s 转换为 JPEG", strings.ToUpper(format)))
+	} else if hasJPEGMetadata(data) {
+		changes = append(changes, "去除 EXIF/GPS 元数据")
+	}
+
+	if format == "jpeg" {
+		if orientation := jpegOrientation(data); orientation > 1 {
+			img = applyOrientation(img, orientation)
+			changes = append(changes, fmt.Sprintf("按 EXIF 方向(%d)旋转", orientation))
+		}
+	}
+
+	if opts.Aspect != "" {
+		ratio := supportedAspects[opts.Aspect]
+		fit := opts.Fit
+		if fit == "" {
+			fit = FitPad
+		}
+		if adjusted, ok := fitAspect(img, ratio[0], ratio[1], fit); ok {
+			img = adjusted
+			verb := "填充"
+			if fit == FitCrop {
+				verb = "裁剪"
+			}
+			changes = append(changes, fmt.Sprintf("%s到 %s", verb, opts.Aspect))
+		}
+	}
+
+	if w, h := scaleToFit(img.Bounds().Dx(), img.Bounds().Dy(), opts.MaxEdge); w != img.Bounds().Dx() {
+		img = resize(img, w, h)
+		changes = append(changes, fmt.Sprintf("缩小到 %dx%d", w, h))
+	}
+
+	oversized := opts.MaxBytes > 0 && int64(len(data)) > opts.MaxBytes
+	if len(changes) == 0 && !oversized {
+		report.Output = path
+		report.OutWidth, report.OutHeight, report.OutBytes = report.Width, report.Height, report.Bytes
+		return report, nil
+	}
+
+	encoded, quality, err := encodeWithinSize(img, opts.Quality, opts.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	if quality < opts.Quality {
+		changes = append(changes, fmt.Sprintf("压缩质量降到 %d", quality))
+	}
+	if bounds := encoded.bounds; bounds.Dx() != img.Bounds().Dx() {
+		changes = append(changes, fmt.Sprintf("为控制文件大小缩小到 %dx%d", bounds.Dx(), bounds.Dy()))
+	}
+
+	if len(changes) == 0 {
+		changes = append(changes, "重新压缩以减小文件")
+	}
+
+	output, err := writeOutput(opts.OutputDir, path, opts, encoded.data)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Output = output
+	report.OutWidth = encoded.bounds.Dx()
+	report.OutHeight = encoded.bounds.Dy()
+	report.OutBytes = int64(len(encoded.data))
+	report.Changes = changes
+	return report, nil
+}
+
+type encodedImage struct {
+	data   []byte
+	bounds image.Rectangle
+}
+
+// encodeWithinSize 编码为 JPEG，超过 maxBytes 时先降低质量，再逐步缩小尺寸
+func encodeWithinSize(img image.Image, quality int, maxBytes int64) (*encodedImage, int, error) {
+	img = flatten(img)
+	for {
+		q := quality
+		for {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: q}); err != nil {
+				return nil, 0, errors.Wrap(err, "编码 JPEG 失败")
+			}
+			if maxBytes <= 0 || int64(buf.Len()) <= maxBytes {
+				return &encodedImage{data: buf.Bytes(), bounds: img.Bounds()}, q, nil
+			}
+			if q-10 < minQuality {
+				break
+			}
+			q -= 10
+		}
+
+		w, h := img.Bounds().Dx()*85/100, img.Bounds().Dy()*85/100
+		if w < 100 || h < 100 {
+			return nil, 0, errors.Errorf("无法把图片压缩到 %d 字节以内", maxBytes)
+		}
+		img = resize(img, w, h)
+	}
+}
+
+// writeOutput 按原路径和选项生成固定的文件名，同一张图片重复处理时覆盖
+func writeOutput(dir, source string, opts Options, data []byte) (string, error) {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "xiaohongshu_images", "prepared")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "创建图片目录失败")
+	}
+
+	key := fmt.Sprintf("%s|%d|%d|%s|%s|%d", source, opts.MaxEdge, opts.MaxBytes, opts.Aspect, opts.Fit, opts.Quality)
+	sum := sha256.Sum256([]byte(key))
+	name := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	output := filepath.Join(dir, fmt.Sprintf("%s_%x.jpg", name, sum[:6]))
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return "", errors.Wrap(err, "保存处理后的图片失败")
+	}
+	return output, nil
+}
+
+// scaleToFit 计算最长边不超过 maxEdge 的尺寸
+func scaleToFit(w, h, maxEdge int) (int, int) {
+	if maxEdge <= 0 || (w <= maxEdge && h <= maxEdge) {
+		return w, h
+	}
+	if w >= h {
+		return maxEdge, max(1, h*maxEdge/w)
+	}
+	return max(1, w*maxEdge/h), maxEdge
+}
+
+// aspectRect 计算调整到 rw:rh 后的画布尺寸（pad）或裁剪区域（crop）。
+// 已经是目标比例（误差 1%以内）时返回 false。
+func aspectRect(w, h, rw, rh int, fit string) (image.Rectangle, bool) {
+	// w/h 与 rw/rh 比较，避免浮点误差
+	diff := w*rh - h*rw
+	if abs(diff)*100 <= h*rw {
+		return image.Rect(0, 0, w, h), false
+	}
+
+	if fit == FitCrop {
+		if diff > 0 {
+			// 太宽，裁掉左右
+			nw := h * rw / rh
+			x := (w - nw) / 2
+			return image.Rect(x, 0, x+nw, h), true
+		}
+		nh := w * rh / rw
+		y := (h - nh) / 2
+		return image.Rect(0, y, w, y+nh), true
+	}
+
+	if diff > 0 {
+		// 太宽，上下填充
+		return image.Rect(0, 0, w, w*rh/rw), true
+	}
+	return image.Rect(0, 0, h*rw/rh, h), true
+}
+
+func fitAspect(img image.Image, rw, rh int, fit string) (image.Image, bool) {
+	b := img.Bounds()
+	rect, ok := aspectRect(b.Dx(), b.Dy(), rw, rh, fit)
+	if !ok {
+		return img, false
+	}
+
+	if fit == FitCrop {
+		dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+		draw.Draw(dst, dst.Bounds(), img, b.Min.Add(rect.Min), draw.Src)
+		return dst, true
+	}
+
+	dst := image.NewRGBA(rect)
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	offset := image.Pt((rect.Dx()-b.Dx())/2, (rect.Dy()-b.Dy())/2)
+	draw.Draw(dst, image.Rectangle{Min: offset, Max: offset.Add(b.Size())}, img, b.Min, draw.Over)
+	return dst, true
+}
+
+func resize(img image.Image, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return dst
+}
+
+// flatten 把透明背景铺成白色，JPEG 不支持透明
+func flatten(img image.Image) image.Image {
+	if _, ok := img.(*image.YCbCr); ok {
+		return img
+	}
+	if _, ok := img.(*image.Gray); ok {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// ParseAspect 规范化宽高比参数，支持 "3:4"、"3x4"、"3/4"
+func ParseAspect(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	normalized := strings.NewReplacer("x", ":", "X", ":", "/", ":", "：", ":").Replace(s)
+	parts := strings.Split(normalized, ":")
+	if len(parts) == 2 {
+		a, errA := strconv.Atoi(strings.TrimSpace(parts[0]))
+		b, errB := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if errA == nil && errB == nil {
+			normalized = fmt.Sprintf("%d:%d", a, b)
+		}
+	}
+	if _, ok := supportedAspects[normalized]; !ok {
+		return "", errors.Errorf("不支持的宽高比: %s（支持 3:4、1:1、4:3）", s)
+	}
+	return normalized, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,180 @@
+package imageprep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleToFit(t *testing.T) {
+	w, h := scaleToFit(4000, 3000, 2000)
+	assert.Equal(t, 2000, w)
+	assert.Equal(t, 1500, h)
+
+	w, h = scaleToFit(1500, 6000, 2000)
+	assert.Equal(t, 500, w)
+	assert.Equal(t, 2000, h)
+
+	w, h = scaleToFit(800, 600, 0)
+	assert.Equal(t, 800, w)
+	assert.Equal(t, 600, h)
+}
+
+func TestAspectRect(t *testing.T) {
+	rect, ok := aspectRect(1000, 1000, 3, 4, FitPad)
+	assert.True(t, ok)
+	assert.Equal(t, image.Rect(0, 0, 1000, 1333), rect)
+
+	rect, ok = aspectRect(1000, 1000, 3, 4, FitCrop)
+	assert.True(t, ok)
+	assert.Equal(t, image.Rect(125, 0, 875, 1000), rect)
+
+	rect, ok = aspectRect(1200, 900, 1, 1, FitCrop)
+	assert.True(t, ok)
+	assert.Equal(t, image.Rect(150, 0, 1050, 900), rect)
+
+	_, ok = aspectRect(1080, 1440, 3, 4, FitPad)
+	assert.False(t, ok, "已经是 3:4")
+}
+
+func TestParseAspect(t *testing.T) {
+	for input, want := range map[string]string{"3:4": "3:4", "3x4": "3:4", " 1/1 ": "1:1", "4：3": "4:3", "": ""} {
+		got, err := ParseAspect(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseAspect("16:9")
+	assert.Error(t, err)
+}
+
+func TestProcessPNGToJPEG(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "photo.png")
+	writePNG(t, src, 2000, 1000)
+
+	report, err := Process(src, Options{MaxEdge: 1000, Aspect: "1:1", OutputDir: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, "png", report.Format)
+	assert.NotEqual(t, src, report.Output)
+	assert.Equal(t, 1000, report.OutWidth)
+	assert.Equal(t, 1000, report.OutHeight)
+	assert.Contains(t, report.Changes, "PNG 转换为 JPEG")
+	assert.Contains(t, report.Changes, "填充到 1:1")
+
+	data, err := os.ReadFile(report.Output)
+	require.NoError(t, err)
+	_, format, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+}
+
+func TestProcessCleanJPEGUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "clean.jpg")
+	require.NoError(t, os.WriteFile(src, encodeJPEG(t, 300, 400), 0644))
+
+	report, err := Process(src, Options{MaxEdge: 2000, Aspect: "3:4", OutputDir: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, src, report.Output)
+	assert.Empty(t, report.Changes)
+}
+
+func TestProcessJPEGWithOrientation(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "rotated.jpg")
+	require.NoError(t, os.WriteFile(src, withOrientation(encodeJPEG(t, 400, 300), 6), 0644))
+
+	report, err := Process(src, Options{OutputDir: dir})
+	require.NoError(t, err)
+
+	assert.Contains(t, report.Changes, "去除 EXIF/GPS 元数据")
+	assert.Equal(t, 300, report.OutWidth)
+	assert.Equal(t, 400, report.OutHeight)
+
+	data, err := os.ReadFile(report.Output)
+	require.NoError(t, err)
+	assert.False(t, hasJPEGMetadata(data))
+}
+
+func TestProcessHEICToJPEG(t *testing.T) {
+	dir := t.TempDir()
+
+	report, err := Process(filepath.Join("testdata", "sample.heic"), Options{MaxEdge: 400, OutputDir: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, "heic", report.Format)
+	assert.Contains(t, report.Changes, "HEIC 转换为 JPEG")
+	assert.Positive(t, report.Width)
+	assert.LessOrEqual(t, max(report.OutWidth, report.OutHeight), 400)
+
+	data, err := os.ReadFile(report.Output)
+	require.NoError(t, err)
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, report.OutWidth, cfg.Width)
+	assert.Equal(t, report.OutHeight, cfg.Height)
+}
+
+func TestProcessRejectsNonImage(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "note.txt")
+	require.NoError(t, os.WriteFile(src, []byte("hello"), 0644))
+
+	_, err := Process(src, Options{})
+	assert.Error(t, err)
+}
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, png.Encode(f, img))
+}
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// withOrientation 在 SOI 之后插入只包含 Orientation 标签的 EXIF 段
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
@@ -0,0 +1,580 @@
+// Package jobqueue 保存和串行执行后台任务。
+// 提交后立即返回 job_id，任务按提交顺序逐个执行，状态和进度写入磁盘，服务重启后未执行的任务继续执行。
+// 定时任务到指定时间才执行，任务需要的文件保存在任务目录中，任务结束后删除。
+package jobqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 任务状态
+const (
+	StatusScheduled = "scheduled"
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// 服务没有运行、错过定时任务执行时间时的处理方式
+const (
+	MissedRun  = "run"  // 服务恢复后立即执行（默认）
+	MissedSkip = "skip" // 不再执行，任务标记为失败
+)
+
+// DefaultRetention 已结束的任务保留时长
+const DefaultRetention = 7 * 24 * time.Hour
+
+// MissedGrace 服务重启时定时任务超过执行时间多久才算错过
+const MissedGrace = 10 * time.Minute
+
+// ErrNotFound 任务不存在
+var ErrNotFound = errors.New("任务不存在")
+
+// filesDirName 保存任务文件的子目录
+const filesDirName = "files"
+
+// idPattern job_id 的格式
+var idPattern = regexp.MustCompile(`^job_[0-9a-f]{24}$`)
+
+// unconfirmedError 任务的操作可能已经生效之后发生的错误
+type unconfirmedError struct {
+	err error
+}
+
+func (e *unconfirmedError) Error() string { return e.err.Error() }
+func (e *unconfirmedError) Unwrap() error { return e.err }
+
+// Unconfirmed 标记 Runner 的错误发生在操作可能已经生效之后（如已经点击了发布但没有等到结果）。
+// 这样结束的任务 Unconfirmed 为 true，需要先确认结果，不能直接重新提交
+func Unconfirmed(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &unconfirmedError{err: err}
+}
+
+// Progress 任务当前执行到的步骤
+type Progress struct {
+	Step    string `json:"step"`
+	Current int    `json:"current,omitempty"`
+	Total   int    `json:"total,omitempty"`
+}
+
+// Job 一个后台任务
+type Job struct {
+	ID              string          `json:"job_id"`
+	Kind            string          `json:"kind"`
+	Summary         string          `json:"summary,omitempty"` // 便于识别任务的简短描述，如笔记标题
+	Status          string          `json:"status"`
+	Progress        *Progress       `json:"progress,omitempty"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested,omitempty"` // 执行中的任务已请求取消，等待当前步骤结束
+	Unconfirmed     bool            `json:"unconfirmed,omitempty"`      // 任务没有成功，但操作可能已经生效（如笔记可能已发布），需要确认后再决定是否重新提交
+	RunAt           *time.Time      `json:"run_at,omitempty"`           // 定时任务的执行时间
+	MissedPolicy    string          `json:"missed_policy,omitempty"`    // 定时任务错过执行时间时的处理方式
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+
+	// Request 任务参数，只保存在磁盘上，查询任务时不返回
+	Request json.RawMessage `json:"request,omitempty"`
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// readyAt 任务可以开始执行的时间：定时任务为执行时间，其余为提交时间
+func (j *Job) readyAt() time.Time {
+	if j.Status == StatusScheduled && j.RunAt != nil {
+		return *j.RunAt
+	}
+	return j.CreatedAt
+}
+
+func (j *Job) clone(withRequest bool) *Job {
+	c := *j
+	if j.Progress != nil {
+		p := *j.Progress
+		c.Progress = &p
+	}
+	if j.RunAt != nil {
+		t := *j.RunAt
+		c.RunAt = &t
+	}
+	if !withRequest {
+		c.Request = nil
+	}
+	return &c
+}
+
+// Runner 执行任务，通过 report 报告进度，返回的结果序列化后保存到任务中
+type Runner func(ctx context.Context, job *Job, report func(Progress)) (any, error)
+
+// Queue 任务队列，同一时间只执行一个任务
+type Queue struct {
+	mu        sync.Mutex
+	dir       string
+	runner    Runner
+	jobs      map[string]*Job
+	cancel    context.CancelFunc // 取消正在执行的任务
+	running   string
+	wake      chan struct{}
+	retention time.Duration
+	now       func() time.Time
+}
+
+// Open 打开任务目录并加载已有任务。上次退出时正在执行的任务标记为失败，
+// 因为无法确定是否已经发布，重新执行可能重复发布；错过执行时间的定时任务按 MissedPolicy 处理
+func Open(dir string, runner Runner) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "创建任务目录失败")
+	}
+
+	q := &Queue{
+		dir:       dir,
+		runner:    runner,
+		jobs:      make(map[string]*Job),
+		wake:      make(chan struct{}, 1),
+		retention: DefaultRetention,
+		now:       time.Now,
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *Queue) load() error {
+	files, err := filepath.Glob(filepath.Join(q.dir, "job_*.json"))
+	if err != nil {
+		return errors.Wrap(err, "读取任务目录失败")
+	}
+
+	now := q.now()
+	var queued, missed int
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logrus.Warnf("读取任务文件失败: %s %v", file, err)
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil || !idPattern.MatchString(job.ID) {
+			logrus.Warnf("任务文件格式错误，已跳过: %s", file)
+			continue
+		}
+
+		switch {
+		case job.Finished() && job.FinishedAt != nil && now.Sub(*job.FinishedAt) > q.retention:
+			os.Remove(file)
+			continue
+		case job.Status == StatusRunning:
+			job.Unconfirmed = true
+			q.finish(&job, StatusFailed, "服务重启，任务中断。请确认笔记是否已发布后再重新提交", now)
+		case job.Status == StatusScheduled && job.RunAt != nil && now.Sub(*job.RunAt) > MissedGrace:
+			missed++
+			if job.MissedPolicy == MissedSkip {
+				q.finish(&job, StatusFailed, "服务在执行时间 "+job.RunAt.Format("2006-01-02 15:04")+" 没有运行，已按设置跳过", now)
+			} else {
+				logrus.Infof("定时任务错过执行时间，立即执行: %s %s", job.ID, job.RunAt.Format("2006-01-02 15:04"))
+			}
+		case !job.Finished():
+			queued++
+		}
+		q.jobs[job.ID] = &job
+	}
+	q.removeOrphanFiles()
+
+	if queued > 0 {
+		logrus.Infof("恢复 %d 个未执行的任务", queued)
+	}
+	if missed > 0 {
+		logrus.Warnf("%d 个定时任务错过了执行时间", missed)
+	}
+	return nil
+}
+
+// removeOrphanFiles 删除已结束或不存在的任务留下的文件目录
+func (q *Queue) removeOrphanFiles() {
+	dirs, err := os.ReadDir(filepath.Join(q.dir, filesDirName))
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if job, ok := q.jobs[dir.Name()]; !ok || job.Finished() {
+			os.RemoveAll(filepath.Join(q.dir, filesDirName, dir.Name()))
+		}
+	}
+}
+
+// Start 启动执行任务的 worker，直到 ctx 结束
+func (q *Queue) Start(ctx context.Context) {
+	go func() {
+		for ctx.Err() == nil {
+			id, wait := q.next()
+			if id == "" {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-q.wake:
+				case <-timer.C:
+				}
+				timer.Stop()
+				continue
+			}
+			q.run(ctx, id)
+		}
+	}()
+}
+
+// Submit 提交任务，request 序列化后保存，执行时通过 Job.Request 读取
+func (q *Queue) Submit(kind, summary string, request any) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return q.add(&Job{ID: id, Kind: kind, Summary: summary, Status: StatusQueued}, request)
+}
+
+// SubmitWithFiles 提交任务，prepare 把任务需要的文件保存到 filesDir 并返回任务参数，
+// filesDir 在任务结束或取消后删除；prepare 返回错误时不创建任务
+func (q *Queue) SubmitWithFiles(kind, summary string, prepare func(filesDir string) (any, error)) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return q.addWithFiles(&Job{ID: id, Kind: kind, Summary: summary, Status: StatusQueued}, prepare)
+}
+
+// Schedule 提交定时任务，到 runAt 才执行。prepare 把任务需要的文件保存到 filesDir 并返回任务参数，
+// filesDir 在任务结束或取消后删除；prepare 返回错误时不创建任务
+func (q *Queue) Schedule(kind, summary string, runAt time.Time, missedPolicy string, prepare func(filesDir string) (any, error)) (*Job, error) {
+	switch missedPolicy {
+	case "":
+		missedPolicy = MissedRun
+	case MissedRun, MissedSkip:
+	default:
+		return nil, errors.Errorf("无效的错过处理方式: %s，可选 run、skip", missedPolicy)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return q.addWithFiles(&Job{ID: id, Kind: kind, Summary: summary, Status: StatusScheduled, RunAt: &runAt, MissedPolicy: missedPolicy}, prepare)
+}
+
+func (q *Queue) addWithFiles(job *Job, prepare func(filesDir string) (any, error)) (*Job, error) {
+	filesDir := q.filesDir(job.ID)
+	if err := os.MkdirAll(filesDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "创建任务文件目录失败")
+	}
+	request, err := prepare(filesDir)
+	if err != nil {
+		os.RemoveAll(filesDir)
+		return nil, err
+	}
+
+	result, err := q.add(job, request)
+	if err != nil {
+		os.RemoveAll(filesDir)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (q *Queue) add(job *Job, request any) (*Job, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "序列化任务参数失败")
+	}
+	now := q.now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	job.Request = data
+
+	q.mu.Lock()
+	if err := q.save(job); err != nil {
+		q.mu.Unlock()
+		return nil, err
+	}
+	q.jobs[job.ID] = job
+	result := job.clone(false)
+	q.mu.Unlock()
+
+	q.notify()
+	if job.RunAt != nil {
+		logrus.Infof("已提交定时任务: %s %s %s 执行时间 %s", job.ID, job.Kind, job.Summary, job.RunAt.Format(time.RFC3339))
+	} else {
+		logrus.Infof("已提交任务: %s %s %s", job.ID, job.Kind, job.Summary)
+	}
+	return result, nil
+}
+
+// Reschedule 修改定时任务的执行时间，只能修改还没有开始执行的定时任务
+func (q *Queue) Reschedule(id string, runAt time.Time) (*Job, error) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	if job.Status != StatusScheduled {
+		q.mu.Unlock()
+		return nil, errors.Errorf("只能修改等待执行的定时任务，当前状态: %s", job.Status)
+	}
+	job.RunAt = &runAt
+	job.UpdatedAt = q.now()
+	if err := q.save(job); err != nil {
+		q.mu.Unlock()
+		return nil, err
+	}
+	result := job.clone(false)
+	q.mu.Unlock()
+
+	q.notify()
+	logrus.Infof("已修改定时任务执行时间: %s %s", id, runAt.Format(time.RFC3339))
+	return result, nil
+}
+
+// Get 返回任务的当前状态
+func (q *Queue) Get(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.clone(false), nil
+}
+
+// List 按提交时间倒序返回任务，status 为空时返回所有状态，limit 为 0 时不限制数量
+func (q *Queue) List(status string, limit int) []*Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job.clone(false))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs
+}
+
+// Cancel 取消任务：等待中和未到时间的定时任务直接取消；执行中的任务中断当前操作，结束后状态变为 canceled
+func (q *Queue) Cancel(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	switch job.Status {
+	case StatusQueued, StatusScheduled:
+		q.finish(job, StatusCanceled, "", q.now())
+	case StatusRunning:
+		job.CancelRequested = true
+		job.UpdatedAt = q.now()
+		if q.running == id && q.cancel != nil {
+			q.cancel()
+		}
+		if err := q.save(job); err != nil {
+			logrus.Warnf("保存任务状态失败: %v", err)
+		}
+	default:
+		return nil, errors.Errorf("任务已结束（%s），无法取消", job.Status)
+	}
+	logrus.Infof("已取消任务: %s", id)
+	return job.clone(false), nil
+}
+
+// next 返回下一个可以执行的任务：等待中的任务和到时间的定时任务按可执行时间先后排列。
+// 没有可执行的任务时返回距离最近的定时任务还要等待多久
+func (q *Queue) next() (string, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var ready *Job
+	wait := time.Duration(math.MaxInt64)
+	for _, job := range q.jobs {
+		if job.Status != StatusQueued && job.Status != StatusScheduled {
+			continue
+		}
+		at := job.readyAt()
+		if at.After(now) && job.Status == StatusScheduled {
+			wait = min(wait, at.Sub(now))
+			continue
+		}
+		if ready == nil || at.Before(ready.readyAt()) || (at.Equal(ready.readyAt()) && job.CreatedAt.Before(ready.CreatedAt)) {
+			ready = job
+		}
+	}
+	if ready == nil {
+		return "", wait
+	}
+	return ready.ID, 0
+}
+
+func (q *Queue) run(ctx context.Context, id string) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	if !ok || (job.Status != StatusQueued && job.Status != StatusScheduled) {
+		q.mu.Unlock()
+		return
+	}
+	now := q.now()
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	job.UpdatedAt = now
+	q.running, q.cancel = id, cancel
+	if err := q.save(job); err != nil {
+		logrus.Warnf("保存任务状态失败: %v", err)
+	}
+	input := job.clone(true)
+	q.mu.Unlock()
+
+	logrus.Infof("开始执行任务: %s %s", id, job.Kind)
+	report := func(p Progress) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		job.Progress = &p
+		job.UpdatedAt = q.now()
+		if err := q.save(job); err != nil {
+			logrus.Warnf("保存任务进度失败: %v", err)
+		}
+	}
+	result, err := q.safeRun(jobCtx, input, report)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now = q.now()
+	q.running, q.cancel = "", nil
+
+	var unconfirmed *unconfirmedError
+	job.Unconfirmed = errors.As(err, &unconfirmed)
+
+	// 请求取消时任务可能已经走完（如已经点击了发布），只有任务确实中断时才记为取消
+	switch {
+	case job.CancelRequested && err != nil:
+		q.finish(job, StatusCanceled, err.Error(), now)
+	case err != nil:
+		q.finish(job, StatusFailed, err.Error(), now)
+	default:
+		var message string
+		if data, marshalErr := json.Marshal(result); marshalErr == nil {
+			job.Result = data
+		} else {
+			message = "序列化任务结果失败: " + marshalErr.Error()
+		}
+		q.finish(job, StatusSucceeded, message, now)
+	}
+	logrus.Infof("任务结束: %s %s", id, job.Status)
+
+	q.prune(now)
+}
+
+// finish 结束任务并删除任务的文件目录，调用方需要持有锁
+func (q *Queue) finish(job *Job, status, message string, now time.Time) {
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+	job.UpdatedAt = now
+	if err := q.save(job); err != nil {
+		logrus.Warnf("保存任务状态失败: %v", err)
+	}
+	os.RemoveAll(q.filesDir(job.ID))
+}
+
+// filesDir 任务文件目录
+func (q *Queue) filesDir(id string) string {
+	return filepath.Join(q.dir, filesDirName, id)
+}
+
+// prune 删除超过保留时长的已结束任务，调用方需要持有锁
+func (q *Queue) prune(now time.Time) {
+	for id, job := range q.jobs {
+		if job.Finished() && job.FinishedAt != nil && now.Sub(*job.FinishedAt) > q.retention {
+			delete(q.jobs, id)
+			os.Remove(filepath.Join(q.dir, id+".json"))
+		}
+	}
+}
+
+// safeRun 执行任务，panic 时任务失败而不是让 worker 退出。
+// 无法知道 panic 时执行到了哪一步，按操作可能已经生效处理
+func (q *Queue) safeRun(ctx context.Context, job *Job, report func(Progress)) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("任务 panic: %s %v", job.ID, r)
+			err = Unconfirmed(errors.Errorf("任务执行出错: %v", r))
+		}
+	}()
+	return q.runner(ctx, job, report)
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save 写入任务文件，调用方需要持有锁
+func (q *Queue) save(job *Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "序列化任务失败")
+	}
+
+	path := filepath.Join(q.dir, job.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrap(err, "保存任务失败")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "保存任务失败")
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "生成 job_id 失败")
+	}
+	return "job_" + hex.EncodeToString(b), nil
+}
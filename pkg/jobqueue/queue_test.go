@@ -0,0 +1,358 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Name string `json:"name"`
+}
+
+func waitStatus(t *testing.T, q *Queue, id, status string) *Job {
+	t.Helper()
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = q.Get(id)
+		return err == nil && job.Status == status
+	}, 3*time.Second, 10*time.Millisecond, "任务 %s 没有变为 %s", id, status)
+	return job
+}
+
+func TestSubmitAndRun(t *testing.T) {
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		var req testRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, err
+		}
+		report(Progress{Step: "uploading", Current: 1, Total: 2})
+		if req.Name == "bad" {
+			return nil, errors.New("发布失败")
+		}
+		return map[string]string{"hello": req.Name}, nil
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	job, err := q.Submit("test", "第一个", testRequest{Name: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Nil(t, job.Request)
+
+	done := waitStatus(t, q, job.ID, StatusSucceeded)
+	assert.JSONEq(t, `{"hello":"a"}`, string(done.Result))
+	assert.Equal(t, &Progress{Step: "uploading", Current: 1, Total: 2}, done.Progress)
+	assert.NotNil(t, done.StartedAt)
+	assert.NotNil(t, done.FinishedAt)
+
+	bad, err := q.Submit("test", "", testRequest{Name: "bad"})
+	require.NoError(t, err)
+	failed := waitStatus(t, q, bad.ID, StatusFailed)
+	assert.Equal(t, "发布失败", failed.Error)
+	assert.False(t, failed.Unconfirmed)
+
+	assert.Len(t, q.List("", 0), 2)
+	assert.Len(t, q.List(StatusFailed, 0), 1)
+	assert.Equal(t, bad.ID, q.List("", 1)[0].ID)
+
+	_, err = q.Get("job_000000000000000000000000")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSubmitWithFiles(t *testing.T) {
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		var req testRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, err
+		}
+		return os.ReadFile(req.Name)
+	})
+	require.NoError(t, err)
+
+	var filesDir string
+	job, err := q.SubmitWithFiles("test", "", func(dir string) (any, error) {
+		filesDir = dir
+		path := filepath.Join(dir, "image.jpg")
+		return testRequest{Name: path}, os.WriteFile(path, []byte("jpg"), 0644)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.FileExists(t, filepath.Join(filesDir, "image.jpg"))
+
+	q.Start(t.Context())
+	waitStatus(t, q, job.ID, StatusSucceeded)
+	assert.NoDirExists(t, filesDir)
+
+	_, err = q.SubmitWithFiles("test", "", func(dir string) (any, error) {
+		filesDir = dir
+		return nil, errors.New("media_id 已过期")
+	})
+	assert.Error(t, err)
+	assert.NoDirExists(t, filesDir)
+}
+
+func TestUnconfirmedFailure(t *testing.T) {
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		var req testRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, err
+		}
+		if req.Name == "panic" {
+			panic("element not found")
+		}
+		return nil, Unconfirmed(errors.New("等待发布结果超时"))
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	job, err := q.Submit("test", "", testRequest{Name: "timeout"})
+	require.NoError(t, err)
+	failed := waitStatus(t, q, job.ID, StatusFailed)
+	assert.True(t, failed.Unconfirmed)
+	assert.Equal(t, "等待发布结果超时", failed.Error)
+
+	// panic 时不知道执行到了哪一步，同样需要确认
+	job, err = q.Submit("test", "", testRequest{Name: "panic"})
+	require.NoError(t, err)
+	failed = waitStatus(t, q, job.ID, StatusFailed)
+	assert.True(t, failed.Unconfirmed)
+}
+
+func TestRunsSerially(t *testing.T) {
+	var running, maxRunning int32
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	var last *Job
+	for i := 0; i < 4; i++ {
+		last, err = q.Submit("test", "", nil)
+		require.NoError(t, err)
+	}
+	waitStatus(t, q, last.ID, StatusSucceeded)
+	assert.Len(t, q.List(StatusSucceeded, 0), 4)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+}
+
+func TestCancel(t *testing.T) {
+	started := make(chan struct{})
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	running, err := q.Submit("test", "", nil)
+	require.NoError(t, err)
+	queued, err := q.Submit("test", "", nil)
+	require.NoError(t, err)
+	<-started
+
+	job, err := q.Cancel(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, job.Status)
+
+	job, err = q.Cancel(running.ID)
+	require.NoError(t, err)
+	assert.True(t, job.CancelRequested)
+	waitStatus(t, q, running.ID, StatusCanceled)
+
+	_, err = q.Cancel(running.ID)
+	assert.ErrorContains(t, err, "已结束")
+}
+
+func TestCancelAfterCompletion(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	q, err := Open(t.TempDir(), func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		close(started)
+		// 模拟已经提交、不再响应取消的步骤
+		<-release
+		return map[string]string{"post_id": "p1"}, nil
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	running, err := q.Submit("test", "", nil)
+	require.NoError(t, err)
+	<-started
+
+	job, err := q.Cancel(running.ID)
+	require.NoError(t, err)
+	assert.True(t, job.CancelRequested)
+	close(release)
+
+	done := waitStatus(t, q, running.ID, StatusSucceeded)
+	assert.JSONEq(t, `{"post_id":"p1"}`, string(done.Result))
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+	q, err := Open(dir, func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		<-block
+		return nil, nil
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	q.Start(ctx)
+
+	interrupted, err := q.Submit("test", "", nil)
+	require.NoError(t, err)
+	waitStatus(t, q, interrupted.ID, StatusRunning)
+	queued, err := q.Submit("test", "", testRequest{Name: "later"})
+	require.NoError(t, err)
+	cancel()
+
+	var ran []string
+	reopened, err := Open(dir, func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		var req testRequest
+		json.Unmarshal(job.Request, &req)
+		ran = append(ran, req.Name)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	job, err := reopened.Get(interrupted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Contains(t, job.Error, "服务重启")
+	assert.True(t, job.Unconfirmed)
+
+	reopened.Start(t.Context())
+	waitStatus(t, reopened, queued.ID, StatusSucceeded)
+	assert.Equal(t, []string{"later"}, ran)
+
+	// 模拟重启前的 worker 结束后不会再取新的任务
+	close(block)
+	waitStatus(t, q, interrupted.ID, StatusSucceeded)
+	job, err = q.Get(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+}
+
+func TestSchedule(t *testing.T) {
+	dir := t.TempDir()
+	var ran []string
+	var mu sync.Mutex
+	q, err := Open(dir, func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		var req testRequest
+		json.Unmarshal(job.Request, &req)
+		mu.Lock()
+		ran = append(ran, req.Name)
+		mu.Unlock()
+		return nil, nil
+	})
+	require.NoError(t, err)
+	q.Start(t.Context())
+
+	var filesDir string
+	prepare := func(name string) func(string) (any, error) {
+		return func(dir string) (any, error) {
+			filesDir = dir
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jpg"), []byte("x"), 0644))
+			return testRequest{Name: name}, nil
+		}
+	}
+
+	later, err := q.Schedule("test", "", time.Now().Add(time.Hour), "", prepare("later"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusScheduled, later.Status)
+	assert.Equal(t, MissedRun, later.MissedPolicy)
+	laterFiles := filesDir
+
+	soon, err := q.Schedule("test", "", time.Now().Add(200*time.Millisecond), MissedSkip, prepare("soon"))
+	require.NoError(t, err)
+	soonFiles := filesDir
+
+	now, err := q.Submit("test", "", testRequest{Name: "now"})
+	require.NoError(t, err)
+	waitStatus(t, q, now.ID, StatusSucceeded)
+	job, err := q.Get(soon.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusScheduled, job.Status, "没到时间的定时任务不应执行")
+
+	waitStatus(t, q, soon.ID, StatusSucceeded)
+	assert.NoDirExists(t, soonFiles)
+
+	// 提前执行时间
+	_, err = q.Reschedule(later.ID, time.Now().Add(100*time.Millisecond))
+	require.NoError(t, err)
+	waitStatus(t, q, later.ID, StatusSucceeded)
+	assert.NoDirExists(t, laterFiles)
+	mu.Lock()
+	assert.Equal(t, []string{"now", "soon", "later"}, ran)
+	mu.Unlock()
+
+	_, err = q.Reschedule(later.ID, time.Now().Add(time.Hour))
+	assert.ErrorContains(t, err, "只能修改等待执行的定时任务")
+
+	canceled, err := q.Schedule("test", "", time.Now().Add(time.Hour), "", prepare("canceled"))
+	require.NoError(t, err)
+	_, err = q.Cancel(canceled.ID)
+	require.NoError(t, err)
+	assert.NoDirExists(t, filesDir)
+
+	_, err = q.Schedule("test", "", time.Now().Add(time.Hour), "", func(dir string) (any, error) {
+		filesDir = dir
+		return nil, errors.New("下载失败")
+	})
+	assert.ErrorContains(t, err, "下载失败")
+	assert.NoDirExists(t, filesDir)
+
+	_, err = q.Schedule("test", "", time.Now().Add(time.Hour), "later", prepare("x"))
+	assert.ErrorContains(t, err, "无效的错过处理方式")
+}
+
+func TestMissedSchedule(t *testing.T) {
+	dir := t.TempDir()
+	q, err := Open(dir, nil)
+	require.NoError(t, err)
+	noop := func(string) (any, error) { return nil, nil }
+
+	skipped, err := q.Schedule("test", "", time.Now().Add(-time.Hour), MissedSkip, noop)
+	require.NoError(t, err)
+	run, err := q.Schedule("test", "", time.Now().Add(-time.Hour), MissedRun, noop)
+	require.NoError(t, err)
+	recent, err := q.Schedule("test", "", time.Now().Add(-time.Minute), MissedSkip, noop)
+	require.NoError(t, err)
+
+	// 服务停止期间错过了执行时间
+	reopened, err := Open(dir, func(ctx context.Context, job *Job, report func(Progress)) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	job, err := reopened.Get(skipped.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Contains(t, job.Error, "已按设置跳过")
+	assert.NoDirExists(t, reopened.filesDir(skipped.ID))
+
+	reopened.Start(t.Context())
+	waitStatus(t, reopened, run.ID, StatusSucceeded)
+	waitStatus(t, reopened, recent.ID, StatusSucceeded)
+}
@@ -0,0 +1,290 @@
+// Package mediastore 保存通过 HTTP 上传的图片和视频，返回可在发布接口中引用的 media_id。
+// 文件保存在受管理的目录中，超过有效期后自动删除。
+package mediastore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 媒体类型
+const (
+	KindImage = "image"
+	KindVideo = "video"
+)
+
+// idPattern media_id 的格式，用于和本地路径、链接区分
+var idPattern = regexp.MustCompile(`^media_[0-9a-f]{24}$`)
+
+// videoExtensions 支持上传的视频格式
+var videoExtensions = map[string]bool{"mp4": true, "mov": true, "m4v": true}
+
+// Item 一个已上传的媒体文件
+type Item struct {
+	ID        string    `json:"media_id"`
+	Kind      string    `json:"kind"` // image | video
+	Filename  string    `json:"filename,omitempty"`
+	MIME      string    `json:"mime"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	path string
+}
+
+// Path 媒体文件的本地路径
+func (i *Item) Path() string {
+	return i.path
+}
+
+// Limits 上传大小上限，0 表示不限制
+type Limits struct {
+	MaxImageBytes int64
+	MaxVideoBytes int64
+}
+
+// Store 媒体文件存储
+type Store struct {
+	mu     sync.Mutex
+	dir    string
+	ttl    time.Duration
+	limits Limits
+	now    func() time.Time
+}
+
+// New 创建媒体存储，dir 不存在时自动创建
+func New(dir string, ttl time.Duration, limits Limits) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "创建媒体目录失败")
+	}
+	return &Store{dir: dir, ttl: ttl, limits: limits, now: time.Now}, nil
+}
+
+// IsMediaID 判断字符串是否为 media_id
+func IsMediaID(s string) bool {
+	return idPattern.MatchString(s)
+}
+
+// Save 保存上传的文件，按文件头识别为图片或视频
+func (s *Store) Save(filename string, r io.Reader) (*Item, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, errors.New("上传的文件为空")
+		}
+		return nil, errors.Wrap(err, "读取上传文件失败")
+	}
+	head = head[:n]
+
+	kind, _ := filetype.Match(head)
+	var mediaKind string
+	var maxBytes int64
+	switch {
+	case filetype.IsImage(head):
+		mediaKind, maxBytes = KindImage, s.limits.MaxImageBytes
+	case videoExtensions[kind.Extension]:
+		mediaKind, maxBytes = KindVideo, s.limits.MaxVideoBytes
+	default:
+		return nil, errors.New("不支持的文件类型，只支持图片和 MP4/MOV 视频")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.dir, id+"."+kind.Extension)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "创建媒体文件失败")
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if maxBytes > 0 {
+		body = io.LimitReader(body, maxBytes+1)
+	}
+	size, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, errors.Wrap(err, "保存媒体文件失败")
+	}
+	if maxBytes > 0 && size > maxBytes {
+		os.Remove(path)
+		return nil, errors.Errorf("文件超过 %dMB 上限", maxBytes>>20)
+	}
+
+	now := s.now()
+	item := &Item{
+		ID:        id,
+		Kind:      mediaKind,
+		Filename:  filepath.Base(filename),
+		MIME:      kind.MIME.Value,
+		Size:      size,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+		path:      path,
+	}
+	if err := s.writeMeta(item); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	logrus.Infof("已保存上传的%s: %s (%d bytes)", mediaKind, id, size)
+	return item, nil
+}
+
+// Get 返回未过期的媒体文件
+func (s *Store) Get(id string) (*Item, error) {
+	if !IsMediaID(id) {
+		return nil, errors.Errorf("无效的 media_id: %s", id)
+	}
+
+	item, err := s.readMeta(id)
+	if err != nil {
+		return nil, errors.Errorf("media_id 不存在或已过期: %s", id)
+	}
+	if s.now().After(item.ExpiresAt) {
+		s.remove(item)
+		return nil, errors.Errorf("media_id 不存在或已过期: %s", id)
+	}
+	return item, nil
+}
+
+// Cleanup 删除已过期的媒体文件，返回删除的数量
+func (s *Store) Cleanup() int {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		logrus.Warnf("读取媒体目录失败: %v", err)
+		return 0
+	}
+
+	removed := 0
+	now := s.now()
+	for _, entry := range entries {
+		name := entry.Name()
+		id := strings.TrimSuffix(name, filepath.Ext(name))
+		if !IsMediaID(id) {
+			continue
+		}
+
+		if !strings.HasSuffix(name, ".json") {
+			// 保存信息之前中断留下的文件，超过有效期后删除
+			if _, err := os.Stat(s.metaPath(id)); os.IsNotExist(err) {
+				if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > s.ttl {
+					os.Remove(filepath.Join(s.dir, name))
+					removed++
+				}
+			}
+			continue
+		}
+
+		item, err := s.readMeta(id)
+		if err != nil || now.After(item.ExpiresAt) {
+			s.remove(&Item{ID: id, path: s.findFile(id)})
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartCleanup 定期清理过期文件，直到 ctx 结束
+func (s *Store) StartCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := s.Cleanup(); n > 0 {
+					logrus.Infof("已清理 %d 个过期的上传文件", n)
+				}
+			}
+		}
+	}()
+}
+
+func (s *Store) metaPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) writeMeta(item *Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return errors.Wrap(err, "序列化媒体信息失败")
+	}
+	if err := os.WriteFile(s.metaPath(item.ID), data, 0644); err != nil {
+		return errors.Wrap(err, "保存媒体信息失败")
+	}
+	return nil
+}
+
+func (s *Store) readMeta(id string) (*Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.metaPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	item.path = s.findFile(id)
+	if item.path == "" {
+		return nil, errors.New("媒体文件不存在")
+	}
+	return &item, nil
+}
+
+// findFile 查找 media_id 对应的媒体文件
+func (s *Store) findFile(id string) string {
+	matches, _ := filepath.Glob(filepath.Join(s.dir, id+".*"))
+	for _, m := range matches {
+		if !strings.HasSuffix(m, ".json") {
+			return m
+		}
+	}
+	return ""
+}
+
+func (s *Store) remove(item *Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item.path != "" {
+		os.Remove(item.path)
+	}
+	os.Remove(s.metaPath(item.ID))
+}
+
+func newID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "生成 media_id 失败")
+	}
+	return "media_" + hex.EncodeToString(b), nil
+}
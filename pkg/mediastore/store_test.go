@@ -0,0 +1,110 @@
+package mediastore
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngData(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	return buf.Bytes()
+}
+
+func mp4Data(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"))
+	return data
+}
+
+func newTestStore(t *testing.T, limits Limits) *Store {
+	t.Helper()
+	s, err := New(t.TempDir(), time.Hour, limits)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSaveAndGet(t *testing.T) {
+	s := newTestStore(t, Limits{})
+
+	img, err := s.Save("photo.png", bytes.NewReader(pngData(t)))
+	require.NoError(t, err)
+	assert.True(t, IsMediaID(img.ID))
+	assert.Equal(t, KindImage, img.Kind)
+	assert.Equal(t, "image/png", img.MIME)
+	assert.Equal(t, "photo.png", img.Filename)
+	assert.Equal(t, ".png", filepath.Ext(img.Path()))
+
+	video, err := s.Save("../clip.mp4", bytes.NewReader(mp4Data(4096)))
+	require.NoError(t, err)
+	assert.Equal(t, KindVideo, video.Kind)
+	assert.Equal(t, int64(4096), video.Size)
+	assert.Equal(t, "clip.mp4", video.Filename)
+
+	got, err := s.Get(video.ID)
+	require.NoError(t, err)
+	assert.Equal(t, video.Path(), got.Path())
+	assert.Equal(t, KindVideo, got.Kind)
+}
+
+func TestSaveRejects(t *testing.T) {
+	s := newTestStore(t, Limits{MaxImageBytes: 1 << 20, MaxVideoBytes: 1024})
+
+	_, err := s.Save("a.txt", strings.NewReader("hello world"))
+	assert.ErrorContains(t, err, "不支持的文件类型")
+
+	_, err = s.Save("empty", strings.NewReader(""))
+	assert.ErrorContains(t, err, "为空")
+
+	_, err = s.Save("big.mp4", bytes.NewReader(mp4Data(2048)))
+	assert.ErrorContains(t, err, "上限")
+
+	entries, _ := os.ReadDir(s.dir)
+	assert.Empty(t, entries)
+}
+
+func TestGetExpiredAndCleanup(t *testing.T) {
+	s := newTestStore(t, Limits{})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	expired, err := s.Save("a.png", bytes.NewReader(pngData(t)))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	fresh, err := s.Save("b.png", bytes.NewReader(pngData(t)))
+	require.NoError(t, err)
+
+	now = now.Add(45 * time.Minute)
+	_, err = s.Get(expired.ID)
+	assert.ErrorContains(t, err, "已过期")
+	_, err = os.Stat(expired.Path())
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = s.Get(fresh.ID)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, 1, s.Cleanup())
+	entries, _ := os.ReadDir(s.dir)
+	assert.Empty(t, entries)
+}
+
+func TestIsMediaID(t *testing.T) {
+	assert.True(t, IsMediaID("media_0123456789abcdef01234567"))
+	assert.False(t, IsMediaID("media_123"))
+	assert.False(t, IsMediaID("/tmp/media_0123456789abcdef01234567.png"))
+	assert.False(t, IsMediaID("https://example.com/a.png"))
+
+	_, err := newTestStore(t, Limits{}).Get("media_0123456789abcdef01234567")
+	assert.ErrorContains(t, err, "不存在")
+}
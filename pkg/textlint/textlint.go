@@ -0,0 +1,202 @@
+// Package textlint 在发布笔记和评论之前检查敏感词。
+// 词库从文本文件加载，每个词带有类别、严重程度和替换建议；匹配时忽略大小写、全角半角和夹在中间的空格符号。
+package textlint
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 严重程度
+const (
+	SeverityHigh = "high" // 拦截模式下阻止发布，未指定时的默认值
+	SeverityLow  = "low"  // 只提示
+)
+
+// Entry 词库中的一个词
+type Entry struct {
+	Word        string
+	Category    string
+	Severity    string
+	Replacement string
+
+	pattern []rune // 归一化后的词
+	ascii   bool   // 纯字母数字的词需要按单词边界匹配
+}
+
+// Finding 一处命中
+type Finding struct {
+	Field       string `json:"field,omitempty"` // 命中的字段，如 title、content、comment
+	Word        string `json:"word"`            // 词库中的词
+	Text        string `json:"text"`            // 原文中命中的内容
+	Offset      int    `json:"offset"`          // 在原文中的位置（按字符计）
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Replacement string `json:"replacement,omitempty"`
+
+	end int
+}
+
+// Linter 敏感词检查器
+type Linter struct {
+	entries []Entry
+}
+
+// New 用词条创建检查器，归一化后为空的词会被忽略
+func New(entries []Entry) *Linter {
+	l := &Linter{}
+	for _, e := range entries {
+		pattern, _ := normalize(e.Word)
+		if len(pattern) == 0 {
+			continue
+		}
+		e.pattern = pattern
+		e.ascii = isASCIIWord(pattern)
+		if e.Severity == "" {
+			e.Severity = SeverityHigh
+		}
+		l.entries = append(l.entries, e)
+	}
+	return l
+}
+
+// Len 词库中的词数
+func (l *Linter) Len() int {
+	return len(l.entries)
+}
+
+// Lint 返回文本中所有命中，按位置排序；被更长命中包含的短命中会被去掉
+func (l *Linter) Lint(text string) []Finding {
+	if l == nil || len(l.entries) == 0 || text == "" {
+		return nil
+	}
+
+	runes := []rune(text)
+	norm, positions := normalize(text)
+
+	var findings []Finding
+	for _, e := range l.entries {
+		for start := 0; start+len(e.pattern) <= len(norm); start++ {
+			if !hasPrefix(norm[start:], e.pattern) {
+				continue
+			}
+			end := start + len(e.pattern)
+			if e.ascii && (start > 0 && isASCIIAlnum(norm[start-1]) || end < len(norm) && isASCIIAlnum(norm[end])) {
+				continue
+			}
+
+			from, to := positions[start], positions[end-1]+1
+			findings = append(findings, Finding{
+				Word:        e.Word,
+				Text:        string(runes[from:to]),
+				Offset:      from,
+				Category:    e.Category,
+				Severity:    e.Severity,
+				Replacement: e.Replacement,
+				end:         to,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Offset != findings[j].Offset {
+			return findings[i].Offset < findings[j].Offset
+		}
+		return findings[i].end > findings[j].end
+	})
+
+	kept := findings[:0]
+	coveredTo := -1
+	for _, f := range findings {
+		if f.end <= coveredTo {
+			continue
+		}
+		kept = append(kept, f)
+		coveredTo = f.end
+	}
+	return kept
+}
+
+// Suggest 用替换建议改写文本，没有替换建议的命中保持原样
+func Suggest(text string, findings []Finding) string {
+	runes := []rune(text)
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		if f.Replacement == "" || f.Offset < last {
+			continue
+		}
+		b.WriteString(string(runes[last:f.Offset]))
+		b.WriteString(f.Replacement)
+		last = f.end
+	}
+	b.WriteString(string(runes[last:]))
+	return b.String()
+}
+
+// Blocking 返回拦截模式下会阻止发布的命中，即 high 级别的命中
+func Blocking(findings []Finding) []Finding {
+	var blocking []Finding
+	for _, f := range findings {
+		if f.Severity == SeverityHigh {
+			blocking = append(blocking, f)
+		}
+	}
+	return blocking
+}
+
+// normalize 转换为小写半角，去掉空白和分隔符号，返回归一化后的字符及其在原文中的位置
+func normalize(s string) ([]rune, []int) {
+	var out []rune
+	var positions []int
+	for i, r := range []rune(s) {
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E: // 全角 ASCII
+			r -= 0xFEE0
+		case r == 0x3000:
+			r = ' '
+		}
+		if isSeparator(r) {
+			continue
+		}
+		out = append(out, unicode.ToLower(r))
+		positions = append(positions, i)
+	}
+	return out, positions
+}
+
+// isSeparator 常被用来拆开敏感词的空白和符号
+func isSeparator(r rune) bool {
+	if unicode.IsSpace(r) {
+		return true
+	}
+	switch r {
+	case '.', '-', '_', '*', '|', '/', '\\', '·', '~', '^', '`', '\'', '"',
+		'\u200b', '\u200c', '\u200d', '\ufeff': // 零宽字符
+		return true
+	}
+	return false
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isASCIIWord(pattern []rune) bool {
+	for _, r := range pattern {
+		if !isASCIIAlnum(r) {
+			return false
+		}
+	}
+	return true
+}
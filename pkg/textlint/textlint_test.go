@@ -0,0 +1,110 @@
+package textlint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLinter(t *testing.T) *Linter {
+	t.Helper()
+	entries, err := ParseWordList(strings.NewReader(`
+# 测试词库
+[导流 high]
+微信 | | | 私信
+vx | | | 私信
+加微信
+[极限词 low]
+最好 | | | 很好
+根治 | 医疗 | high
+`))
+	require.NoError(t, err)
+	return New(entries)
+}
+
+func words(findings []Finding) []string {
+	var ws []string
+	for _, f := range findings {
+		ws = append(ws, f.Word)
+	}
+	return ws
+}
+
+func TestParseWordList(t *testing.T) {
+	l := testLinter(t)
+	require.Equal(t, 5, l.Len())
+	assert.Equal(t, "医疗", l.entries[4].Category)
+	assert.Equal(t, SeverityHigh, l.entries[4].Severity)
+	assert.Equal(t, "极限词", l.entries[3].Category)
+	assert.Equal(t, SeverityLow, l.entries[3].Severity)
+	assert.Equal(t, "很好", l.entries[3].Replacement)
+
+	_, err := ParseWordList(strings.NewReader("词 | 类别 | medium"))
+	assert.ErrorContains(t, err, "第 1 行")
+	_, err = ParseWordList(strings.NewReader("[a b c]"))
+	assert.Error(t, err)
+
+	assert.Greater(t, Default().Len(), 10)
+}
+
+func TestLint(t *testing.T) {
+	l := testLinter(t)
+
+	t.Run("位置和原文", func(t *testing.T) {
+		findings := l.Lint("这是最好的，欢迎来聊")
+		require.Equal(t, []string{"最好"}, words(findings))
+		assert.Equal(t, 2, findings[0].Offset)
+		assert.Equal(t, "最好", findings[0].Text)
+	})
+
+	t.Run("忽略大小写、全角和分隔符", func(t *testing.T) {
+		findings := l.Lint("有事找我 ＶＸ，或者微.信")
+		require.Equal(t, []string{"vx", "微信"}, words(findings))
+		assert.Equal(t, "ＶＸ", findings[0].Text)
+		assert.Equal(t, "微.信", findings[1].Text)
+	})
+
+	t.Run("英文词按单词边界匹配", func(t *testing.T) {
+		assert.Empty(t, l.Lint("devx 和 vxlan"))
+		assert.Len(t, l.Lint("vx: abc"), 1)
+	})
+
+	t.Run("长词覆盖短词", func(t *testing.T) {
+		assert.Equal(t, []string{"加微信"}, words(l.Lint("可以加微信")))
+	})
+
+	t.Run("没有命中", func(t *testing.T) {
+		assert.Empty(t, l.Lint("今天天气不错"))
+		assert.Empty(t, (*Linter)(nil).Lint("微信"))
+	})
+}
+
+func TestSuggestAndBlocking(t *testing.T) {
+	l := testLinter(t)
+	text := "最好的药，根治失眠，vx 联系"
+	findings := l.Lint(text)
+
+	assert.Equal(t, "很好的药，根治失眠，私信 联系", Suggest(text, findings))
+	assert.Equal(t, []string{"根治", "vx"}, words(Blocking(findings)))
+	assert.Empty(t, Blocking(l.Lint("最好的")))
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("甲\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("乙 | 类别 | low\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.md"), []byte("丙\n"), 0644))
+	extra := filepath.Join(t.TempDir(), "extra.txt")
+	require.NoError(t, os.WriteFile(extra, []byte("丁\n"), 0644))
+
+	l, err := LoadFiles([]string{dir, extra})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"甲", "乙", "丁"}, words(l.Lint("甲乙丙丁")))
+
+	_, err = LoadFiles([]string{filepath.Join(dir, "missing.txt")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package textlint
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed default_words.txt
+var defaultWords string
+
+// Default 使用内置词库创建检查器
+func Default() *Linter {
+	entries, err := ParseWordList(strings.NewReader(defaultWords))
+	if err != nil {
+		panic(err)
+	}
+	return New(entries)
+}
+
+// ParseWordList 解析词库文件。每行一个词：
+//
+//	词语 | 类别 | 严重程度(high|low) | 替换建议
+//
+// 后三项可以省略；[类别 严重程度] 为后面的词设置默认值，# 开头的行为注释
+func ParseWordList(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	category, severity := "", SeverityHigh
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			fields := strings.Fields(line[1 : len(line)-1])
+			if len(fields) == 0 || len(fields) > 2 {
+				return nil, errors.Errorf("第 %d 行: 无效的分组 %s", lineNo, line)
+			}
+			category, severity = fields[0], SeverityHigh
+			if len(fields) == 2 {
+				if !validSeverity(fields[1]) {
+					return nil, errors.Errorf("第 %d 行: 无效的严重程度 %s", lineNo, fields[1])
+				}
+				severity = fields[1]
+			}
+			continue
+		}
+
+		parts := strings.Split(line, "|")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if len(parts) > 4 {
+			return nil, errors.Errorf("第 %d 行: 字段过多", lineNo)
+		}
+		for len(parts) < 4 {
+			parts = append(parts, "")
+		}
+
+		entry := Entry{Word: parts[0], Category: category, Severity: severity, Replacement: parts[3]}
+		if entry.Word == "" {
+			return nil, errors.Errorf("第 %d 行: 词语不能为空", lineNo)
+		}
+		if parts[1] != "" {
+			entry.Category = parts[1]
+		}
+		if parts[2] != "" {
+			if !validSeverity(parts[2]) {
+				return nil, errors.Errorf("第 %d 行: 无效的严重程度 %s", lineNo, parts[2])
+			}
+			entry.Severity = parts[2]
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "读取词库失败")
+	}
+	return entries, nil
+}
+
+// LoadFiles 从文件或目录加载词库，目录中的 .txt 文件按文件名顺序加载
+func LoadFiles(paths []string) (*Linter, error) {
+	var entries []Entry
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		files, err := wordFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, errors.Wrap(err, "打开词库失败")
+			}
+			parsed, err := ParseWordList(f)
+			f.Close()
+			if err != nil {
+				return nil, errors.Wrapf(err, "词库 %s", file)
+			}
+			entries = append(entries, parsed...)
+		}
+	}
+	return New(entries), nil
+}
+
+func wordFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "词库不存在")
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.txt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "读取词库目录失败")
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func validSeverity(s string) bool {
+	return s == SeverityHigh || s == SeverityLow
+}
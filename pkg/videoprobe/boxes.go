@@ -0,0 +1,338 @@
+package videoprobe
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxMoovSize moov 只包含索引信息，超过这个大小视为文件损坏
+const maxMoovSize = 256 << 20
+
+// topLevelBoxes 可以出现在 MP4/MOV 文件开头的 box 类型，用于识别文件格式
+var topLevelBoxes = map[string]bool{
+	"ftyp": true, "moov": true, "mdat": true, "free": true,
+	"skip": true, "wide": true, "pnot": true, "uuid": true,
+}
+
+// box 一个 ISO BMFF box：type 和不含头部的数据
+type box struct {
+	Type string
+	Data []byte
+}
+
+// track 从 trak 中解析出的轨道信息
+type track struct {
+	handler  string // vide | soun
+	codec    string
+	width    int
+	height   int
+	rotation int
+}
+
+// parseContainer 遍历顶层 box，读取 ftyp 和 moov，跳过 mdat 等大块数据
+func parseContainer(r io.ReadSeeker, size int64) (*Info, error) {
+	info := &Info{Container: "mov"}
+	var moov []byte
+	var offset int64
+	first := true
+
+	for offset < size {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, errors.Wrap(err, "读取视频文件失败")
+		}
+
+		var header [16]byte
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			break
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		if first {
+			if !topLevelBoxes[boxType] {
+				return nil, errors.New("不支持的视频格式，只支持 MP4/MOV")
+			}
+			first = false
+		}
+
+		switch boxSize {
+		case 0: // 一直延续到文件末尾
+			boxSize = size - offset
+		case 1: // 64 位大小
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return nil, errors.New("视频文件不完整")
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || offset+boxSize > size {
+			return nil, errors.Errorf("视频文件不完整或已损坏（%s box 越界）", strings.TrimSpace(boxType))
+		}
+
+		dataSize := boxSize - headerSize
+		switch boxType {
+		case "ftyp":
+			data, err := readBytes(r, dataSize, 1024)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) >= 4 {
+				info.Brand = strings.TrimSpace(string(data[:4]))
+				if info.Brand != "qt" {
+					info.Container = "mp4"
+				}
+			}
+		case "moov":
+			if dataSize > maxMoovSize {
+				return nil, errors.New("视频索引信息过大，文件可能已损坏")
+			}
+			data, err := readBytes(r, dataSize, dataSize)
+			if err != nil {
+				return nil, err
+			}
+			moov = data
+		}
+
+		offset += boxSize
+	}
+
+	if first {
+		return nil, errors.New("视频文件为空")
+	}
+	if moov == nil {
+		return nil, errors.New("视频文件缺少 moov 索引，可能没有录制完成或已损坏")
+	}
+	if err := parseMoov(moov, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// readBytes 读取 n 字节，最多保留 limit 字节
+func readBytes(r io.Reader, n, limit int64) ([]byte, error) {
+	if n > limit {
+		n = limit
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "读取视频文件失败")
+	}
+	return data, nil
+}
+
+// parseMoov 从 moov 中读取时长和音视频轨道
+func parseMoov(moov []byte, info *Info) error {
+	children, err := splitBoxes(moov)
+	if err != nil {
+		return err
+	}
+
+	var videoTrack *track
+	for _, child := range children {
+		switch child.Type {
+		case "mvhd":
+			info.Duration = parseMvhd(child.Data)
+		case "trak":
+			t, err := parseTrak(child.Data)
+			if err != nil {
+				return err
+			}
+			switch t.handler {
+			case "vide":
+				if videoTrack == nil {
+					videoTrack = t
+				}
+			case "soun":
+				if info.AudioCodec == "" {
+					info.AudioCodec = codecName(t.codec)
+				}
+			}
+		}
+	}
+
+	if videoTrack == nil {
+		return errors.New("文件中没有视频轨道")
+	}
+
+	info.Width = videoTrack.width
+	info.Height = videoTrack.height
+	info.Rotation = videoTrack.rotation
+	info.VideoCodec = codecName(videoTrack.codec)
+	info.DisplayWidth, info.DisplayHeight = info.Width, info.Height
+	if info.Rotation == 90 || info.Rotation == 270 {
+		info.DisplayWidth, info.DisplayHeight = info.Height, info.Width
+	}
+	return nil
+}
+
+// parseTrak 读取轨道类型、编码、尺寸和旋转角度
+func parseTrak(data []byte) (*track, error) {
+	children, err := splitBoxes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &track{}
+	for _, child := range children {
+		switch child.Type {
+		case "tkhd":
+			t.width, t.height, t.rotation = parseTkhd(child.Data)
+		case "mdia":
+			if err := parseMdia(child.Data, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+// parseMdia 读取 hdlr 中的轨道类型和 stsd 中的编码
+func parseMdia(data []byte, t *track) error {
+	children, err := splitBoxes(data)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		switch child.Type {
+		case "hdlr":
+			// version/flags(4) + pre_defined(4) + handler_type(4)
+			if len(child.Data) >= 12 {
+				t.handler = string(child.Data[8:12])
+			}
+		case "minf":
+			stsd, err := findBox(child.Data, "stbl", "stsd")
+			if err != nil {
+				return err
+			}
+			// version/flags(4) + entry_count(4) + 第一个 entry 的 size(4) + format(4)
+			if len(stsd) >= 16 {
+				t.codec = string(stsd[12:16])
+			}
+		}
+	}
+	return nil
+}
+
+// parseMvhd 读取影片时长（秒）
+func parseMvhd(data []byte) float64 {
+	if len(data) < 4 {
+		return 0
+	}
+	var timescale, duration uint64
+	if data[0] == 1 {
+		// version/flags(4) + creation(8) + modification(8) + timescale(4) + duration(8)
+		if len(data) < 32 {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(data[20:24]))
+		duration = binary.BigEndian.Uint64(data[24:32])
+	} else {
+		// version/flags(4) + creation(4) + modification(4) + timescale(4) + duration(4)
+		if len(data) < 20 {
+			return 0
+		}
+		timescale = uint64(binary.BigEndian.Uint32(data[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(data[16:20]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return math.Round(float64(duration)/float64(timescale)*1000) / 1000
+}
+
+// parseTkhd 读取轨道的宽高（16.16 定点数）和变换矩阵中的旋转角度
+func parseTkhd(data []byte) (width, height, rotation int) {
+	if len(data) < 4 {
+		return 0, 0, 0
+	}
+	matrixOffset := 40
+	if data[0] == 1 {
+		matrixOffset = 52
+	}
+	if len(data) < matrixOffset+44 {
+		return 0, 0, 0
+	}
+
+	matrix := data[matrixOffset : matrixOffset+36]
+	a := float64(int32(binary.BigEndian.Uint32(matrix[0:4]))) / 65536
+	b := float64(int32(binary.BigEndian.Uint32(matrix[4:8]))) / 65536
+	rotation = matrixRotation(a, b)
+
+	width = int(binary.BigEndian.Uint32(data[matrixOffset+36:matrixOffset+40]) >> 16)
+	height = int(binary.BigEndian.Uint32(data[matrixOffset+40:matrixOffset+44]) >> 16)
+	return width, height, rotation
+}
+
+// matrixRotation 由变换矩阵的 a、b 分量算出顺时针旋转角度，取最接近的 90 度倍数
+func matrixRotation(a, b float64) int {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	degrees := math.Atan2(b, a) * 180 / math.Pi
+	rotation := int(math.Round(degrees/90)) * 90
+	return (rotation%360 + 360) % 360
+}
+
+// splitBoxes 把一段数据拆分为连续的子 box
+func splitBoxes(data []byte) ([]box, error) {
+	var boxes []box
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[:4]))
+		boxType := string(data[4:8])
+		headerSize := uint64(8)
+
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, errors.New("视频索引信息已损坏")
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			headerSize = 16
+		}
+		if size < headerSize || size > uint64(len(data)) {
+			return nil, errors.Errorf("视频索引信息已损坏（%s box 越界）", strings.TrimSpace(boxType))
+		}
+
+		boxes = append(boxes, box{Type: boxType, Data: data[headerSize:size]})
+		data = data[size:]
+	}
+	return boxes, nil
+}
+
+// findBox 按路径查找嵌套的子 box，没有找到时返回 nil
+func findBox(data []byte, path ...string) ([]byte, error) {
+	for _, name := range path {
+		children, err := splitBoxes(data)
+		if err != nil {
+			return nil, err
+		}
+		var found []byte
+		for _, child := range children {
+			if child.Type == name {
+				found = child.Data
+				break
+			}
+		}
+		if found == nil {
+			return nil, nil
+		}
+		data = found
+	}
+	return data, nil
+}
+
+// codecName 把容器中的编码标识转换为通用名称
+func codecName(fourcc string) string {
+	if name, ok := codecNames[fourcc]; ok {
+		return name
+	}
+	return strings.TrimSpace(fourcc)
+}
@@ -0,0 +1,141 @@
+// Package videoprobe 在上传到小红书之前检查视频文件：
+// 直接解析 MP4/MOV 容器（不依赖 ffmpeg），读取时长、分辨率、编码、旋转角度和文件大小，
+// 并按平台限制提前拒绝不支持的文件，避免打开浏览器后长时间等待。
+package videoprobe
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Info 视频文件的元数据
+type Info struct {
+	Path          string  `json:"path"`
+	Container     string  `json:"container"` // mp4 | mov
+	Brand         string  `json:"brand,omitempty"`
+	Duration      float64 `json:"duration_seconds"`
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	Rotation      int     `json:"rotation,omitempty"` // 播放时顺时针旋转的角度：0、90、180、270
+	DisplayWidth  int     `json:"display_width"`      // 按旋转角度换算后的显示宽度
+	DisplayHeight int     `json:"display_height"`
+	VideoCodec    string  `json:"video_codec"` // 如 h264、h265，未知编码为容器中的原始标识
+	AudioCodec    string  `json:"audio_codec,omitempty"`
+	Size          int64   `json:"size"`
+}
+
+// Limits 平台对上传视频的限制，0 表示不限制
+type Limits struct {
+	MaxBytes    int64
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	MinEdge     int      // 画面短边最小像素
+	Codecs      []string // 支持的视频编码，为空不检查
+}
+
+// DefaultLimits 创作者中心网页端的上传限制：mp4/mov、H.264/H.265、60 分钟以内、20GB 以内
+var DefaultLimits = Limits{
+	MaxBytes:    20 << 30,
+	MinDuration: time.Second,
+	MaxDuration: 60 * time.Minute,
+	MinEdge:     240,
+	Codecs:      []string{"h264", "h265"},
+}
+
+// codecNames 容器中的编码标识到通用名称
+var codecNames = map[string]string{
+	"avc1": "h264",
+	"avc3": "h264",
+	"hvc1": "h265",
+	"hev1": "h265",
+	"mp4v": "mpeg4",
+	"av01": "av1",
+	"vp09": "vp9",
+	"apcn": "prores",
+	"apch": "prores",
+	"apcs": "prores",
+	"apco": "prores",
+	"ap4h": "prores",
+	"mp4a": "aac",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	"Opus": "opus",
+	"lpcm": "pcm",
+	"sowt": "pcm",
+	"twos": "pcm",
+}
+
+// Probe 读取视频文件的元数据
+func Probe(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "打开视频文件失败")
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "读取视频文件信息失败")
+	}
+	if stat.IsDir() {
+		return nil, errors.Errorf("不是视频文件: %s", path)
+	}
+
+	info, err := parseContainer(f, stat.Size())
+	if err != nil {
+		return nil, err
+	}
+	info.Path = path
+	info.Size = stat.Size()
+	return info, nil
+}
+
+// Validate 检查视频是否满足平台限制，返回的错误说明具体原因
+func Validate(info *Info, limits Limits) error {
+	if limits.MaxBytes > 0 && info.Size > limits.MaxBytes {
+		return errors.Errorf("视频文件 %s 超过上限 %s", formatBytes(info.Size), formatBytes(limits.MaxBytes))
+	}
+
+	duration := time.Duration(info.Duration * float64(time.Second))
+	if limits.MinDuration > 0 && duration < limits.MinDuration {
+		return errors.Errorf("视频时长 %.1f 秒，短于最少 %s", info.Duration, limits.MinDuration)
+	}
+	if limits.MaxDuration > 0 && duration > limits.MaxDuration {
+		return errors.Errorf("视频时长 %s 超过上限 %s", duration.Round(time.Second), limits.MaxDuration)
+	}
+
+	if limits.MinEdge > 0 && min(info.Width, info.Height) < limits.MinEdge {
+		return errors.Errorf("视频分辨率 %dx%d 过低，短边至少 %d 像素", info.Width, info.Height, limits.MinEdge)
+	}
+
+	if len(limits.Codecs) > 0 {
+		supported := false
+		for _, c := range limits.Codecs {
+			if strings.EqualFold(c, info.VideoCodec) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.Errorf("不支持的视频编码 %s（支持 %s）", info.VideoCodec, strings.Join(limits.Codecs, "、"))
+		}
+	}
+
+	return nil
+}
+
+// formatBytes 把字节数格式化为便于阅读的大小
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.2fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%dKB", n>>10)
+	}
+}
@@ -0,0 +1,183 @@
+package videoprobe
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mkbox(boxType string, payloads ...[]byte) []byte {
+	var data []byte
+	for _, p := range payloads {
+		data = append(data, p...)
+	}
+	out := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint32(out[:4], uint32(8+len(data)))
+	copy(out[4:8], boxType)
+	return append(out, data...)
+}
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func mvhd(timescale, duration uint32) []byte {
+	payload := append(make([]byte, 12), u32(timescale)...)
+	payload = append(payload, u32(duration)...)
+	return mkbox("mvhd", payload, make([]byte, 80))
+}
+
+func tkhd(width, height int, a, b, c, d int32) []byte {
+	payload := make([]byte, 40)
+	for _, v := range []int32{a, b, 0, c, d, 0, 0, 0, 0x40000000} {
+		payload = append(payload, u32(uint32(v))...)
+	}
+	payload = append(payload, u32(uint32(width)<<16)...)
+	payload = append(payload, u32(uint32(height)<<16)...)
+	return mkbox("tkhd", payload)
+}
+
+func trak(handler, codec string, tk []byte) []byte {
+	hdlr := mkbox("hdlr", make([]byte, 8), []byte(handler), make([]byte, 12))
+	stsd := mkbox("stsd", make([]byte, 4), u32(1), mkbox(codec, make([]byte, 20)))
+	minf := mkbox("minf", mkbox("stbl", stsd))
+	return mkbox("trak", tk, mkbox("mdia", hdlr, minf))
+}
+
+const one = 1 << 16
+
+func writeVideo(t *testing.T, boxes ...[]byte) string {
+	t.Helper()
+	var data []byte
+	for _, b := range boxes {
+		data = append(data, b...)
+	}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestProbeMP4(t *testing.T) {
+	path := writeVideo(t,
+		mkbox("ftyp", []byte("isom"), u32(512), []byte("isomiso2avc1mp41")),
+		mkbox("mdat", make([]byte, 1024)),
+		mkbox("moov",
+			mvhd(1000, 15500),
+			trak("vide", "avc1", tkhd(1920, 1080, 0, one, -one, 0)),
+			trak("soun", "mp4a", tkhd(0, 0, one, 0, 0, one)),
+		),
+	)
+
+	info, err := Probe(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mp4", info.Container)
+	assert.Equal(t, "isom", info.Brand)
+	assert.Equal(t, 15.5, info.Duration)
+	assert.Equal(t, 1920, info.Width)
+	assert.Equal(t, 1080, info.Height)
+	assert.Equal(t, 90, info.Rotation)
+	assert.Equal(t, 1080, info.DisplayWidth)
+	assert.Equal(t, 1920, info.DisplayHeight)
+	assert.Equal(t, "h264", info.VideoCodec)
+	assert.Equal(t, "aac", info.AudioCodec)
+	stat, _ := os.Stat(path)
+	assert.Equal(t, stat.Size(), info.Size)
+}
+
+func TestProbeMOV(t *testing.T) {
+	path := writeVideo(t,
+		mkbox("ftyp", []byte("qt  "), u32(0), []byte("qt  ")),
+		mkbox("moov",
+			mvhd(600, 6000),
+			trak("vide", "hvc1", tkhd(1080, 1920, one, 0, 0, one)),
+		),
+		mkbox("mdat", make([]byte, 64)),
+	)
+
+	info, err := Probe(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mov", info.Container)
+	assert.Equal(t, 10.0, info.Duration)
+	assert.Equal(t, 0, info.Rotation)
+	assert.Equal(t, "h265", info.VideoCodec)
+	assert.Empty(t, info.AudioCodec)
+}
+
+func TestProbeErrors(t *testing.T) {
+	t.Run("not a video", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "a.mp4")
+		require.NoError(t, os.WriteFile(path, []byte("\x89PNG\r\n\x1a\nxxxxxxxx"), 0644))
+		_, err := Probe(path)
+		assert.ErrorContains(t, err, "不支持的视频格式")
+	})
+
+	t.Run("missing moov", func(t *testing.T) {
+		path := writeVideo(t, mkbox("ftyp", []byte("isom"), u32(0)), mkbox("mdat", make([]byte, 16)))
+		_, err := Probe(path)
+		assert.ErrorContains(t, err, "moov")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		data := mkbox("ftyp", []byte("isom"), u32(0))
+		data = append(data, u32(4096)...)
+		data = append(data, []byte("mdat")...)
+		path := writeVideo(t, data)
+		_, err := Probe(path)
+		assert.ErrorContains(t, err, "不完整")
+	})
+
+	t.Run("no video track", func(t *testing.T) {
+		path := writeVideo(t,
+			mkbox("ftyp", []byte("M4A "), u32(0)),
+			mkbox("moov", mvhd(1000, 5000), trak("soun", "mp4a", tkhd(0, 0, one, 0, 0, one))),
+		)
+		_, err := Probe(path)
+		assert.ErrorContains(t, err, "没有视频轨道")
+	})
+}
+
+func TestMatrixRotation(t *testing.T) {
+	assert.Equal(t, 0, matrixRotation(1, 0))
+	assert.Equal(t, 90, matrixRotation(0, 1))
+	assert.Equal(t, 180, matrixRotation(-1, 0))
+	assert.Equal(t, 270, matrixRotation(0, -1))
+}
+
+func TestValidate(t *testing.T) {
+	base := Info{Duration: 30, Width: 1080, Height: 1920, VideoCodec: "h264", Size: 50 << 20}
+
+	tests := []struct {
+		name    string
+		modify  func(*Info)
+		wantErr string
+	}{
+		{name: "ok", modify: func(*Info) {}},
+		{name: "too large", modify: func(i *Info) { i.Size = 21 << 30 }, wantErr: "超过上限"},
+		{name: "too short", modify: func(i *Info) { i.Duration = 0.5 }, wantErr: "短于"},
+		{name: "too long", modify: func(i *Info) { i.Duration = 3601 }, wantErr: "时长"},
+		{name: "low resolution", modify: func(i *Info) { i.Width, i.Height = 160, 120 }, wantErr: "分辨率"},
+		{name: "codec", modify: func(i *Info) { i.VideoCodec = "prores" }, wantErr: "编码"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := base
+			tt.modify(&info)
+			err := Validate(&info, DefaultLimits)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	assert.NoError(t, Validate(&Info{Duration: 7200, VideoCodec: "av1"}, Limits{MaxDuration: 3 * time.Hour}))
+}
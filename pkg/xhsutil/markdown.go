@@ -0,0 +1,258 @@
+package xhsutil
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// 正文格式
+const (
+	ContentFormatPlain    = "plain"    // 按原样输入（默认）
+	ContentFormatMarkdown = "markdown" // 先把 Markdown 转换为小红书的纯文本风格
+)
+
+// 转换 Markdown 时使用的符号
+const (
+	headingEmoji1     = "📌"
+	headingEmoji2     = "✨"
+	headingEmojiOther = "💡"
+	bulletEmoji       = "🔸"
+	subBulletEmoji    = "▫️"
+	taskTodoEmoji     = "⬜"
+	taskDoneEmoji     = "✅"
+)
+
+var (
+	fencePattern     = regexp.MustCompile("^\\s*(```|~~~)")
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	quotePattern     = regexp.MustCompile(`^\s*>\s?`)
+	taskPattern      = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	bulletPattern    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedPattern   = regexp.MustCompile(`^(\s*)(\d{1,9})[.)]\s+(.*)$`)
+	rulePattern      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	tableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	refDefPattern    = regexp.MustCompile(`^\s*\[[^\]]+\]:\s+\S+`)
+	escapePattern    = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!>~|])")
+	codePattern      = regexp.MustCompile("`+([^`]+?)`+")
+	imagePattern     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	linkPattern      = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	refLinkPattern   = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	autolinkPattern  = regexp.MustCompile(`<(?:https?|mailto):[^>\s]+>`)
+	urlPattern       = regexp.MustCompile(`https?://[A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]+`)
+	boldPattern      = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	strikePattern    = regexp.MustCompile(`~~(.+?)~~`)
+	starItalic       = regexp.MustCompile(`\*([^*\s](?:[^*]*?[^*\s])?)\*`)
+	underscoreItalic = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\s](?:[^_]*?[^_\s])?)_($|[^\p{L}\p{N}_])`)
+	htmlTagPattern   = regexp.MustCompile(`</?(?:br|p|b|strong|i|em|u|span|div)\b[^>]*>`)
+	tagPattern       = regexp.MustCompile(`(^|\s)#([\p{L}\p{N}\p{M}_·\-]+)(?:\[话题\])?#?`)
+	spacesPattern    = regexp.MustCompile(`[ \t]{2,}`)
+	punctPattern     = regexp.MustCompile(`[ \t]+([，。！？、；：）」』】])`)
+)
+
+// escapeBase 转义字符在转换过程中替换为私有区字符，避免被当作 Markdown 标记或标签，转换结束后还原
+const escapeBase = '\uE000'
+
+const escapable = "\\`*_{}[]()#+-.!>~|"
+
+// ParseContentFormat 校验正文格式，空字符串表示默认的 plain
+func ParseContentFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return ContentFormatPlain, nil
+	case ContentFormatPlain, ContentFormatMarkdown:
+		return format, nil
+	}
+	return "", errors.Errorf("无效的 content_format: %s，可选 plain、markdown", format)
+}
+
+// MarkdownToText 把 Markdown 正文转换为小红书的纯文本风格，返回转换后的正文和正文中的 #标签：
+// 标题转换为 emoji 开头的行，列表转换为 emoji 项目符号，去掉粗体、斜体等标记，链接只保留文字，
+// 图片和网址删除，代码块保留内容；#标签 从正文中取出，由调用方放到 Tags 中；
+// 去掉行尾空白，连续的空行合并为一个
+func MarkdownToText(md string) (string, []string) {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+
+	var lines []string
+	var tags []string
+	inFence := false
+	for _, line := range strings.Split(md, "\n") {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+			continue
+		}
+
+		if tableSepPattern.MatchString(line) || refDefPattern.MatchString(line) {
+			// 表格分隔行和链接定义直接删除
+			continue
+		}
+
+		converted, heading := convertBlock(line)
+		if converted == "" {
+			lines = append(lines, "")
+			continue
+		}
+		converted, lineTags := extractTags(converted)
+		tags = append(tags, lineTags...)
+		if strings.TrimSpace(converted) == "" {
+			// 只有标签的行整行删除
+			continue
+		}
+		if heading && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, converted)
+	}
+
+	return restoreEscapes(normalizeSpacing(lines)), MergeTags(nil, tags)
+}
+
+// convertBlock 转换一行的块级标记，heading 表示这一行是标题
+func convertBlock(line string) (string, bool) {
+	if strings.TrimSpace(line) == "" || rulePattern.MatchString(line) {
+		return "", false
+	}
+
+	for quotePattern.MatchString(line) {
+		line = quotePattern.ReplaceAllString(line, "")
+	}
+
+	if m := headingPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+		emoji := headingEmojiOther
+		switch len(m[1]) {
+		case 1:
+			emoji = headingEmoji1
+		case 2:
+			emoji = headingEmoji2
+		}
+		return emoji + " " + convertInline(m[2]), true
+	}
+	if m := taskPattern.FindStringSubmatch(line); m != nil {
+		emoji := taskTodoEmoji
+		if m[2] != " " {
+			emoji = taskDoneEmoji
+		}
+		return emoji + " " + convertInline(m[3]), false
+	}
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		emoji := bulletEmoji
+		if indentWidth(m[1]) >= 2 {
+			emoji = subBulletEmoji
+		}
+		return emoji + " " + convertInline(m[2]), false
+	}
+	if m := orderedPattern.FindStringSubmatch(line); m != nil {
+		return numberEmoji(m[2]) + " " + convertInline(m[3]), false
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "|") {
+		cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+		for i, cell := range cells {
+			cells[i] = strings.TrimSpace(cell)
+		}
+		return convertInline(strings.Join(cells, " | ")), false
+	}
+	return convertInline(trimmed), false
+}
+
+// convertInline 去掉行内标记
+func convertInline(s string) string {
+	s = escapePattern.ReplaceAllStringFunc(s, func(m string) string {
+		return string(escapeBase + rune(strings.IndexByte(escapable, m[1])))
+	})
+	s = codePattern.ReplaceAllString(s, "$1")
+	s = imagePattern.ReplaceAllString(s, "")
+	s = linkPattern.ReplaceAllString(s, "$1")
+	s = refLinkPattern.ReplaceAllString(s, "$1")
+	s = autolinkPattern.ReplaceAllString(s, "")
+	s = urlPattern.ReplaceAllString(s, "")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = boldPattern.ReplaceAllString(s, "$1$2")
+	s = strikePattern.ReplaceAllString(s, "$1")
+	s = starItalic.ReplaceAllString(s, "$1")
+	s = underscoreItalic.ReplaceAllString(s, "$1$2$3")
+	s = punctPattern.ReplaceAllString(s, "$1") // 删除链接后留在中文标点前的空格
+	return strings.TrimSpace(spacesPattern.ReplaceAllString(s, " "))
+}
+
+// extractTags 取出一行中的 #标签，纯数字的 #1 这类不算标签
+func extractTags(line string) (string, []string) {
+	var tags []string
+	line = tagPattern.ReplaceAllStringFunc(line, func(m string) string {
+		sub := tagPattern.FindStringSubmatch(m)
+		if strings.IndexFunc(sub[2], func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			return m
+		}
+		tags = append(tags, sub[2])
+		return sub[1]
+	})
+	return strings.TrimSpace(spacesPattern.ReplaceAllString(line, " ")), tags
+}
+
+// normalizeSpacing 去掉行尾空白，合并连续的空行，去掉开头和结尾的空行
+func normalizeSpacing(lines []string) string {
+	var out []string
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// restoreEscapes 还原转义的字符
+func restoreEscapes(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= escapeBase && r < escapeBase+rune(len(escapable)) {
+			return rune(escapable[r-escapeBase])
+		}
+		return r
+	}, s)
+}
+
+// numberEmoji 有序列表的序号，1 至 10 使用数字 emoji
+func numberEmoji(num string) string {
+	n, _ := strconv.Atoi(num)
+	switch {
+	case n == 10:
+		return "🔟"
+	case n >= 0 && n <= 9:
+		return strconv.Itoa(n) + "\uFE0F\u20E3"
+	}
+	return num + "."
+}
+
+// indentWidth 缩进宽度，tab 算 4 个空格
+func indentWidth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "    "))
+}
+
+// MergeTags 把 extra 中的标签追加到 tags 后面，tags 保持不变；extra 中的标签去掉 # 和空白，
+// 跳过空标签和已有的标签（不区分大小写）
+func MergeTags(tags, extra []string) []string {
+	merged := append([]string{}, tags...)
+	seen := make(map[string]bool, len(tags)+len(extra))
+	for _, tag := range tags {
+		seen[strings.ToLower(NormalizeTag(tag))] = true
+	}
+	for _, raw := range extra {
+		tag := NormalizeTag(raw)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
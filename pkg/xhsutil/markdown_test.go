@@ -0,0 +1,99 @@
+package xhsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownToText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		wantTags []string
+	}{
+		{
+			name:  "标题",
+			input: "# 周末去哪儿\n正文\n## 交通\n### 小贴士 ##",
+			want:  "📌 周末去哪儿\n正文\n\n✨ 交通\n\n💡 小贴士",
+		},
+		{
+			name:  "列表",
+			input: "- 帐篷\n* 睡袋\n  - 防潮垫\n1. 出发\n2) 扎营\n12. 返程",
+			want:  "🔸 帐篷\n🔸 睡袋\n▫️ 防潮垫\n1️⃣ 出发\n2️⃣ 扎营\n12. 返程",
+		},
+		{
+			name:  "任务列表",
+			input: "- [ ] 订酒店\n- [x] 买票",
+			want:  "⬜ 订酒店\n✅ 买票",
+		},
+		{
+			name:  "行内标记",
+			input: "**一定要带**防晒，*真的*很晒，~~不用~~带伞，`SPF50` 就够，snake_case_name 保留",
+			want:  "一定要带防晒，真的很晒，不用带伞，SPF50 就够，snake_case_name 保留",
+		},
+		{
+			name:  "链接和图片",
+			input: "详情见[攻略](https://example.com/a)和 <https://example.com/b>，网址 https://example.com/c，再见\n![图](a.png)",
+			want:  "详情见攻略和，网址，再见",
+		},
+		{
+			name:     "标签",
+			input:    "今天去了海边 #旅行 #海边[话题]# 很开心\n排名 #1\n#周末 #旅行",
+			want:     "今天去了海边 很开心\n排名 #1",
+			wantTags: []string{"旅行", "海边", "周末"},
+		},
+		{
+			name:  "转义",
+			input: `\*不是斜体\* \#不是标签 1\. 不是列表`,
+			want:  `*不是斜体* #不是标签 1. 不是列表`,
+		},
+		{
+			name:  "空行和引用",
+			input: "\n\n第一段   \n\n\n\n> 引用的话\n\n---\n\n最后一段\n\n",
+			want:  "第一段\n\n引用的话\n\n最后一段",
+		},
+		{
+			name:  "代码块保留原样",
+			input: "```go\n**x** := 1\n```",
+			want:  "**x** := 1",
+		},
+		{
+			name:  "表格",
+			input: "| 项目 | 价格 |\n| --- | ---: |\n| 门票 | 80 |",
+			want:  "项目 | 价格\n门票 | 80",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, tags := MarkdownToText(tt.input)
+			assert.Equal(t, tt.want, got)
+			if tt.wantTags == nil {
+				assert.Empty(t, tags)
+			} else {
+				assert.Equal(t, tt.wantTags, tags)
+			}
+		})
+	}
+}
+
+func TestParseContentFormat(t *testing.T) {
+	format, err := ParseContentFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, ContentFormatPlain, format)
+
+	format, err = ParseContentFormat("markdown")
+	require.NoError(t, err)
+	assert.Equal(t, ContentFormatMarkdown, format)
+
+	_, err = ParseContentFormat("html")
+	assert.Error(t, err)
+}
+
+func TestMergeTags(t *testing.T) {
+	assert.Equal(t, []string{"#旅行", "美食", "海边"}, MergeTags([]string{"#旅行", "美食"}, []string{"旅行", "#海边", "美食", "", "海边"}))
+	assert.Equal(t, []string{"Travel"}, MergeTags(nil, []string{"Travel", "travel"}))
+}
@@ -0,0 +1,220 @@
+package xhsutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// 发布编辑器的限制
+const (
+	MaxTitleLength   = 20
+	MaxContentLength = 1000
+	MinImages        = 1
+	MaxImages        = 18
+	MaxTags          = 10
+)
+
+// 问题的严重程度
+const (
+	SeverityError   = "error"   // 发布会失败
+	SeverityWarning = "warning" // 可以发布，但结果和请求不一致
+)
+
+// bracketEmojiPattern 小红书表情代码，如 [笑哭R]、[赞R]，编辑器中显示为一个表情
+var bracketEmojiPattern = regexp.MustCompile(`\[[\p{Han}A-Za-z0-9]{1,10}R\]`)
+
+// Note 待校验的笔记内容
+type Note struct {
+	Title      string
+	Content    string
+	Tags       []string
+	ImageCount int
+	Video      bool // 视频笔记不检查图片数量
+}
+
+// Problem 一个校验问题
+type Problem struct {
+	Field    string `json:"field"` // title | content | tags | images
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// NoteReport 笔记校验结果
+type NoteReport struct {
+	Valid         bool      `json:"valid"`
+	TitleLength   int       `json:"title_length"`
+	ContentLength int       `json:"content_length"` // 正文加上自动追加的标签后的长度
+	TagsLength    int       `json:"tags_length"`    // 其中标签占用的长度
+	ImageCount    int       `json:"image_count"`
+	Problems      []Problem `json:"problems"`
+}
+
+// NormalizeTag 去掉标签首尾空白和开头的 #
+func NormalizeTag(tag string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+}
+
+// CalcContentLength 按编辑器的规则计算正文长度：
+// 与标题相同，非 ASCII 字符（包括 emoji 的每个 UTF-16 码元）算 2 字节、ASCII 算 1 字节，向上取整除以 2；
+// [笑哭R] 这样的表情代码在编辑器中是一个表情，按 1 个字计算
+func CalcContentLength(s string) int {
+	return (contentUnits(s) + 1) / 2
+}
+
+// contentUnits 按字节规则计算的长度（除以 2 之前）
+func contentUnits(s string) int {
+	units := 0
+	last := 0
+	for _, loc := range bracketEmojiPattern.FindAllStringIndex(s, -1) {
+		units += byteUnits(s[last:loc[0]]) + 2
+		last = loc[1]
+	}
+	return units + byteUnits(s[last:])
+}
+
+func byteUnits(s string) int {
+	n := 0
+	for _, c := range utf16.Encode([]rune(s)) {
+		if c > 127 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// TagsSuffix 发布时追加在正文末尾的标签文本：空一行后依次输入 "#标签 "。
+// 空标签和重复标签会被跳过，最多 MaxTags 个
+func TagsSuffix(tags []string) string {
+	kept := keptTags(tags)
+	if len(kept) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n")
+	for _, tag := range kept {
+		b.WriteString("#" + tag + " ")
+	}
+	return b.String()
+}
+
+// keptTags 按发布时的规则保留的标签
+func keptTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var kept []string
+	for _, raw := range tags {
+		tag := NormalizeTag(raw)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, tag)
+		if len(kept) == MaxTags {
+			break
+		}
+	}
+	return kept
+}
+
+// ValidateNote 不打开浏览器，一次性检查笔记的所有问题
+func ValidateNote(n Note) NoteReport {
+	report := NoteReport{
+		TitleLength: CalcTitleLength(n.Title),
+		ImageCount:  n.ImageCount,
+		Problems:    []Problem{},
+	}
+
+	addError := func(field, format string, args ...any) {
+		report.Problems = append(report.Problems, Problem{Field: field, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(field, format string, args ...any) {
+		report.Problems = append(report.Problems, Problem{Field: field, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// 标题
+	if strings.TrimSpace(n.Title) == "" {
+		addError("title", "标题不能为空")
+	} else if report.TitleLength > MaxTitleLength {
+		addError("title", "标题长度 %d 超过 %d 字限制", report.TitleLength, MaxTitleLength)
+	}
+
+	// 正文（含追加的标签）
+	suffix := TagsSuffix(n.Tags)
+	bodyUnits := contentUnits(n.Content)
+	totalUnits := bodyUnits + contentUnits(suffix)
+	report.ContentLength = (totalUnits + 1) / 2
+	report.TagsLength = report.ContentLength - (bodyUnits+1)/2
+
+	if strings.TrimSpace(n.Content) == "" {
+		addError("content", "正文不能为空")
+	}
+	if report.ContentLength > MaxContentLength {
+		if report.TagsLength > 0 && (bodyUnits+1)/2 <= MaxContentLength {
+			addError("content", "正文 %d 字加上标签 %d 字共 %d 字，超过 %d 字限制", report.ContentLength-report.TagsLength, report.TagsLength, report.ContentLength, MaxContentLength)
+		} else {
+			addError("content", "正文长度 %d 超过 %d 字限制", report.ContentLength, MaxContentLength)
+		}
+	}
+
+	// 标签
+	var empty, duplicate int
+	seen := make(map[string]bool, len(n.Tags))
+	unique := 0
+	for _, raw := range n.Tags {
+		tag := NormalizeTag(raw)
+		switch {
+		case tag == "":
+			empty++
+		case seen[strings.ToLower(tag)]:
+			duplicate++
+		default:
+			seen[strings.ToLower(tag)] = true
+			unique++
+		}
+	}
+	if empty > 0 {
+		addWarning("tags", "%d 个空标签会被忽略", empty)
+	}
+	if duplicate > 0 {
+		addWarning("tags", "%d 个重复标签会被忽略", duplicate)
+	}
+	if unique > MaxTags {
+		addWarning("tags", "标签共 %d 个，超过 %d 个的部分不会添加", unique, MaxTags)
+	}
+
+	// 图片
+	if n.Video {
+		if n.ImageCount > 0 {
+			addError("images", "视频笔记不能同时上传图片")
+		}
+	} else if n.ImageCount < MinImages {
+		addError("images", "至少需要 %d 张图片", MinImages)
+	} else if n.ImageCount > MaxImages {
+		addError("images", "图片共 %d 张，最多 %d 张", n.ImageCount, MaxImages)
+	}
+
+	report.Valid = true
+	for _, p := range report.Problems {
+		if p.Severity == SeverityError {
+			report.Valid = false
+			break
+		}
+	}
+	return report
+}
+
+// Errors 返回所有错误级别的问题，用于发布前拦截
+func (r NoteReport) Errors() []string {
+	var errs []string
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			errs = append(errs, p.Message)
+		}
+	}
+	return errs
+}
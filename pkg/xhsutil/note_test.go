@@ -0,0 +1,110 @@
+package xhsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcContentLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "空字符串", input: "", want: 0},
+		{name: "纯中文", input: "今天天气很好", want: 6},
+		{name: "英文按半个字", input: "hello world", want: 6},
+		{name: "换行按半个字", input: "第一行\n第二行", want: 7},
+		{name: "emoji算2个字", input: "😀", want: 2},
+		{name: "表情代码算1个字", input: "[笑哭R]", want: 1},
+		{name: "文字加表情代码", input: "好吃[赞R][赞R]", want: 4},
+		{name: "不是表情代码的方括号", input: "[注意]", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalcContentLength(tt.input))
+		})
+	}
+}
+
+func TestTagsSuffix(t *testing.T) {
+	assert.Equal(t, "", TagsSuffix(nil))
+	assert.Equal(t, "", TagsSuffix([]string{" ", "#"}))
+	assert.Equal(t, "\n\n#美食 #旅行 ", TagsSuffix([]string{"#美食", "旅行", "美食", ""}))
+
+	many := make([]string, 12)
+	for i := range many {
+		many[i] = strings.Repeat("标", i+1)
+	}
+	assert.Equal(t, 10, strings.Count(TagsSuffix(many), "#"))
+}
+
+func problemFields(r NoteReport, severity string) []string {
+	var fields []string
+	for _, p := range r.Problems {
+		if p.Severity == severity {
+			fields = append(fields, p.Field)
+		}
+	}
+	return fields
+}
+
+func TestValidateNote(t *testing.T) {
+	t.Run("合法的图文笔记", func(t *testing.T) {
+		r := ValidateNote(Note{Title: "周末去哪玩", Content: "推荐三个地方", Tags: []string{"旅行"}, ImageCount: 3})
+		assert.True(t, r.Valid)
+		assert.Empty(t, r.Problems)
+		assert.Equal(t, 5, r.TitleLength)
+		assert.Equal(t, 6, r.ContentLength-r.TagsLength)
+		assert.Equal(t, CalcContentLength("推荐三个地方\n\n#旅行 "), r.ContentLength)
+	})
+
+	t.Run("一次返回所有问题", func(t *testing.T) {
+		r := ValidateNote(Note{
+			Title:      strings.Repeat("长", 21),
+			Content:    strings.Repeat("字", 1001),
+			Tags:       []string{"a", "A", ""},
+			ImageCount: 19,
+		})
+		assert.False(t, r.Valid)
+		assert.ElementsMatch(t, []string{"title", "content", "images"}, problemFields(r, SeverityError))
+		assert.ElementsMatch(t, []string{"tags", "tags"}, problemFields(r, SeverityWarning))
+		assert.Len(t, r.Errors(), 3)
+	})
+
+	t.Run("标签让正文超长", func(t *testing.T) {
+		r := ValidateNote(Note{Title: "标题", Content: strings.Repeat("字", 995), Tags: []string{"美食探店"}, ImageCount: 1})
+		assert.False(t, r.Valid)
+		assert.Equal(t, 995, r.ContentLength-r.TagsLength)
+		assert.Greater(t, r.TagsLength, 5)
+		assert.Contains(t, r.Errors()[0], "加上标签")
+	})
+
+	t.Run("空标题和空正文", func(t *testing.T) {
+		r := ValidateNote(Note{Title: " ", Content: "", ImageCount: 1})
+		assert.ElementsMatch(t, []string{"title", "content"}, problemFields(r, SeverityError))
+	})
+
+	t.Run("图片数量", func(t *testing.T) {
+		assert.Equal(t, []string{"images"}, problemFields(ValidateNote(Note{Title: "a", Content: "b"}), SeverityError))
+		assert.True(t, ValidateNote(Note{Title: "a", Content: "b", ImageCount: 18}).Valid)
+	})
+
+	t.Run("超过10个标签只是警告", func(t *testing.T) {
+		tags := make([]string, 11)
+		for i := range tags {
+			tags[i] = strings.Repeat("t", i+1)
+		}
+		r := ValidateNote(Note{Title: "a", Content: "b", Tags: tags, ImageCount: 1})
+		assert.True(t, r.Valid)
+		assert.Equal(t, []string{"tags"}, problemFields(r, SeverityWarning))
+	})
+
+	t.Run("视频笔记", func(t *testing.T) {
+		assert.True(t, ValidateNote(Note{Title: "a", Content: "b", Video: true}).Valid)
+		assert.False(t, ValidateNote(Note{Title: "a", Content: "b", Video: true, ImageCount: 1}).Valid)
+	})
+}
@@ -1,6 +1,29 @@
 package xhsutil
 
-import "unicode/utf16"
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// 标题超长时的处理方式
+const (
+	TitleOverflowError      = "error"        // 返回错误（默认）
+	TitleOverflowTruncate   = "truncate"     // 截断标题
+	TitleOverflowMoveToBody = "move_to_body" // 截断标题，并把完整标题放到正文开头
+)
+
+// TitleAdjustment 标题超长时做的调整
+type TitleAdjustment struct {
+	Mode           string `json:"mode"`
+	OriginalTitle  string `json:"original_title"`
+	OriginalLength int    `json:"original_length"`
+	Title          string `json:"title"` // 实际使用的标题
+	Length         int    `json:"length"`
+	MovedToBody    bool   `json:"moved_to_body,omitempty"` // 完整标题已放到正文开头
+}
 
 // CalcTitleLength 计算小红书标题长度
 // 规则：非ASCII字符(中文、全角符号等)算2字节，ASCII字符算1字节，最终结果向上取整除以2
@@ -15,3 +38,96 @@ func CalcTitleLength(s string) int {
 	}
 	return (byteLen + 1) / 2
 }
+
+// ParseTitleOverflow 校验标题超长处理方式，空字符串表示默认的 error
+func ParseTitleOverflow(mode string) (string, error) {
+	switch mode {
+	case "":
+		return TitleOverflowError, nil
+	case TitleOverflowError, TitleOverflowTruncate, TitleOverflowMoveToBody:
+		return mode, nil
+	}
+	return "", errors.Errorf("无效的 title_overflow: %s，可选 error、truncate、move_to_body", mode)
+}
+
+// TruncateTitle 按 CalcTitleLength 的规则把标题截断到 maxLen 以内。
+// 只在字形边界截断，不会拆开 emoji 组合序列或带附加符号的字符，截断后去掉末尾空白
+func TruncateTitle(title string, maxLen int) string {
+	if CalcTitleLength(title) <= maxLen {
+		return title
+	}
+
+	var b strings.Builder
+	for _, g := range graphemes(title) {
+		if CalcTitleLength(b.String()+g) > maxLen {
+			break
+		}
+		b.WriteString(g)
+	}
+	return strings.TrimRightFunc(b.String(), unicode.IsSpace)
+}
+
+// FitTitle 按 mode 处理超长标题，返回实际使用的标题和正文；标题没有超长时 adjustment 为 nil，
+// mode 为 error 时原样返回，由后续校验报错
+func FitTitle(title, content, mode string) (string, string, *TitleAdjustment) {
+	length := CalcTitleLength(title)
+	if length <= MaxTitleLength || mode == TitleOverflowError || mode == "" {
+		return title, content, nil
+	}
+
+	fitted := TruncateTitle(title, MaxTitleLength)
+	adjustment := &TitleAdjustment{
+		Mode:           mode,
+		OriginalTitle:  title,
+		OriginalLength: length,
+		Title:          fitted,
+		Length:         CalcTitleLength(fitted),
+	}
+	if mode == TitleOverflowMoveToBody {
+		content = strings.TrimSpace(title) + "\n" + content
+		adjustment.MovedToBody = true
+	}
+	return fitted, content, adjustment
+}
+
+// graphemes 把字符串切分为用户可见的字符：组合附加符号、变体选择符、肤色修饰、
+// 零宽连接的 emoji 序列和国旗（两个区域指示符）都和前面的字符合为一个
+func graphemes(s string) []string {
+	runes := []rune(s)
+	var out []string
+	for i := 0; i < len(runes); {
+		j := i + 1
+		regional := isRegionalIndicator(runes[i])
+		for j < len(runes) {
+			r := runes[j]
+			switch {
+			case isExtend(r):
+				j++
+				continue
+			case r == '\u200d' && j+1 < len(runes): // ZWJ 连接下一个字符
+				j += 2
+				continue
+			case regional && isRegionalIndicator(r):
+				regional = false
+				j++
+				continue
+			}
+			break
+		}
+		out = append(out, string(runes[i:j]))
+		i = j
+	}
+	return out
+}
+
+// isExtend 附着在前一个字符上的码点
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r >= 0xFE00 && r <= 0xFE0F || // 变体选择符
+		r >= 0x1F3FB && r <= 0x1F3FF || // 肤色修饰
+		r >= 0xE0020 && r <= 0xE007F // 标签字符（子区域旗帜）
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -34,3 +34,62 @@ func TestCalcTitleLength(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "没有超长不变", input: "周末去哪玩", want: "周末去哪玩"},
+		{name: "中文截断到20字", input: "一二三四五六七八九十一二三四五六七八九十多余", want: "一二三四五六七八九十一二三四五六七八九十"},
+		{name: "英文按半个字计算", input: "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopq", want: "abcdefghijklmnopqrstuvwxyzabcdefghijklmn"},
+		{name: "不拆开emoji", input: "一二三四五六七八九十一二三四五六七八九😀", want: "一二三四五六七八九十一二三四五六七八九"},
+		{name: "不拆开肤色修饰", input: "一二三四五六七八九十一二三四五六七👍🏻多", want: "一二三四五六七八九十一二三四五六七"},
+		{name: "不拆开ZWJ序列", input: "一二三四五六七八九十一二三四五六👨‍👩‍👧多", want: "一二三四五六七八九十一二三四五六"},
+		{name: "去掉末尾空格", input: "一二三四五六七八九十一二三四五六七八九 十", want: "一二三四五六七八九十一二三四五六七八九"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateTitle(tt.input, MaxTitleLength)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, CalcTitleLength(got), MaxTitleLength)
+		})
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	assert.Equal(t, []string{"a", "é", "🇨🇳", "🇯🇵", "❤️", "👨‍👩‍👧"}, graphemes("aé🇨🇳🇯🇵❤️👨‍👩‍👧"))
+}
+
+func TestFitTitle(t *testing.T) {
+	long := "一二三四五六七八九十一二三四五六七八九十多余"
+
+	title, content, adj := FitTitle("短标题", "正文", TitleOverflowMoveToBody)
+	assert.Equal(t, "短标题", title)
+	assert.Equal(t, "正文", content)
+	assert.Nil(t, adj)
+
+	title, content, adj = FitTitle(long, "正文", TitleOverflowError)
+	assert.Equal(t, long, title)
+	assert.Nil(t, adj)
+
+	title, content, adj = FitTitle(long, "正文", TitleOverflowTruncate)
+	assert.Equal(t, "一二三四五六七八九十一二三四五六七八九十", title)
+	assert.Equal(t, "正文", content)
+	assert.Equal(t, 22, adj.OriginalLength)
+	assert.Equal(t, 20, adj.Length)
+	assert.False(t, adj.MovedToBody)
+
+	title, content, adj = FitTitle(long, "正文", TitleOverflowMoveToBody)
+	assert.Equal(t, 20, CalcTitleLength(title))
+	assert.Equal(t, long+"\n正文", content)
+	assert.True(t, adj.MovedToBody)
+
+	_, err := ParseTitleOverflow("cut")
+	assert.Error(t, err)
+	mode, err := ParseTitleOverflow("")
+	assert.NoError(t, err)
+	assert.Equal(t, TitleOverflowError, mode)
+}
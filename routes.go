@@ -41,8 +41,24 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 		api.GET("/login/status", appServer.checkLoginStatusHandler)
 		api.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
 		api.DELETE("/login/cookies", appServer.deleteCookiesHandler)
+		api.POST("/login/cookies", appServer.importCookiesHandler)
+		api.GET("/login/cookies", appServer.exportCookiesHandler)
 		api.POST("/publish", appServer.publishHandler)
 		api.POST("/publish_video", appServer.publishVideoHandler)
+		api.GET("/publish/topics", appServer.suggestTopicsHandler)
+		api.POST("/publish/validate", appServer.validateNoteHandler)
+		api.POST("/lint", appServer.lintTextHandler)
+		api.POST("/media", appServer.uploadMediaHandler)
+		api.GET("/jobs", appServer.listJobsHandler)
+		api.GET("/jobs/:job_id", appServer.getJobHandler)
+		api.POST("/jobs/:job_id/cancel", appServer.cancelJobHandler)
+		api.POST("/jobs/:job_id/reschedule", appServer.rescheduleJobHandler)
+		api.GET("/drafts", appServer.listDraftsHandler)
+		api.POST("/drafts/publish", appServer.publishDraftHandler)
+		api.DELETE("/drafts/:draft_id", appServer.deleteDraftHandler)
+		api.GET("/creator/notes", appServer.listMyNotesHandler)
+		api.PUT("/creator/notes/:note_id", appServer.editNoteHandler)
+		api.DELETE("/creator/notes/:note_id", appServer.deleteNoteHandler)
 		api.GET("/feeds/list", appServer.listFeedsHandler)
 		api.GET("/feeds/saved", appServer.listSavedFeedsHandler)
 		api.GET("/feeds/search", appServer.searchFeedsHandler)
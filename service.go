@@ -3,42 +3,148 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/headless_browser"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/idempotency"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jobqueue"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mediastore"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textlint"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/videoprobe"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // XiaohongshuService 小红书业务服务
-type XiaohongshuService struct{}
+type XiaohongshuService struct {
+	loginCache  *loginStatusCache
+	media       *mediastore.Store  // 通过 /api/v1/media 上传的文件，nil 表示不可用
+	linter      *textlint.Linter   // 敏感词检查
+	jobs        *jobqueue.Queue    // 异步发布任务，nil 表示不可用
+	idempotency *idempotency.Store // idempotency_key 记录，nil 表示不可用
+	publishSlot chan struct{}      // 同一时间只发布一篇笔记，同步发布和异步任务共用
+}
+
+// mediaCleanupInterval 清理过期上传文件的间隔
+const mediaCleanupInterval = 10 * time.Minute
+
+// inlineImageTTL base64 图片解码后的文件保留时长，只需要覆盖一次发布。
+// 异步任务保存的是原始 base64 数据，执行时才解码；只有 schedule_mode=local 的任务会在提交时把图片复制到任务目录
+const inlineImageTTL = time.Hour
+
+// preparedImageTTL 预处理后图片的保留时长，图片在发布时生成并马上上传，之后不再需要
+const preparedImageTTL = time.Hour
 
 // NewXiaohongshuService 创建小红书服务实例
 func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+	s := &XiaohongshuService{
+		loginCache:  newLoginStatusCache(loginStatusCacheTTL),
+		linter:      newLinter(),
+		idempotency: openIdempotency(),
+		publishSlot: make(chan struct{}, 1),
+	}
+
+	media, err := mediastore.New(configs.GetMediaPath(), configs.GetMediaTTL(), mediastore.Limits{
+		MaxImageBytes: downloader.MaxInlineImageBytes,
+		MaxVideoBytes: configs.GetVideoDownloadMaxBytes(),
+	})
+	if err != nil {
+		logrus.Warnf("初始化上传文件目录失败，媒体上传不可用: %v", err)
+	} else {
+		media.Cleanup()
+		media.StartCleanup(context.Background(), mediaCleanupInterval)
+		s.media = media
+	}
+
+	downloader.CleanupInlineImages(configs.GetImagesPath(), inlineImageTTL)
+	downloader.StartInlineImageCleanup(context.Background(), configs.GetImagesPath(), inlineImageTTL, mediaCleanupInterval)
+	imageprep.CleanupOutputs(configs.GetPreparedImagesPath(), preparedImageTTL)
+	imageprep.StartOutputCleanup(context.Background(), configs.GetPreparedImagesPath(), preparedImageTTL, mediaCleanupInterval)
+
+	s.startJobs()
+
+	return s
+}
+
+// newLinter 加载敏感词词库，未配置时使用内置词库；词库有误时直接退出，避免带着错误的规则运行
+func newLinter() *textlint.Linter {
+	files := configs.GetLintWordFiles()
+	if len(files) == 0 {
+		return textlint.Default()
+	}
+	linter, err := textlint.LoadFiles(files)
+	if err != nil {
+		logrus.Fatalf("加载敏感词词库失败: %v", err)
+	}
+	logrus.Infof("已加载敏感词词库: %d 个词", linter.Len())
+	return linter
 }
 
 // PublishRequest 发布请求
 type PublishRequest struct {
-	Title      string   `json:"title" binding:"required"`
-	Content    string   `json:"content" binding:"required"`
-	Images     []string `json:"images" binding:"required,min=1"`
-	Tags       []string `json:"tags,omitempty"`
-	ScheduleAt string   `json:"schedule_at,omitempty"` // 定时发布时间，ISO8601格式，为空则立即发布
+	Title          string   `json:"title" binding:"required"`
+	Content        string   `json:"content" binding:"required"`
+	Images         []string `json:"images" binding:"required,min=1"` // HTTP 链接、本地路径、file:// 链接或 base64 图片
+	Tags           []string `json:"tags,omitempty"`
+	ScheduleAt     string   `json:"schedule_at,omitempty"`     // 定时发布时间，ISO8601格式，为空则立即发布
+	ScheduleMode   string   `json:"schedule_mode,omitempty"`   // 定时方式：platform（默认，1小时至14天）| local（由服务到时间发布）
+	MissedPolicy   string   `json:"missed_policy,omitempty"`   // local 定时错过时间时：run（服务恢复后立即发布，默认）| skip
+	Draft          bool     `json:"draft,omitempty"`           // 只保存到草稿箱，不发布
+	Visibility     string   `json:"visibility,omitempty"`      // 可见范围：公开（默认）/仅自己可见/仅互关好友可见
+	Location       string   `json:"location,omitempty"`        // 地点关键词，自动选择最匹配的地点
+	Mentions       []string `json:"mentions,omitempty"`        // 正文中要 @ 的用户：昵称、用户ID 或 "昵称:用户ID"
+	ImageAspect    string   `json:"image_aspect,omitempty"`    // 上传前把图片调整为 3:4、1:1 或 4:3，为空不调整
+	ImageFit       string   `json:"image_fit,omitempty"`       // 调整宽高比的方式：pad（填充，默认）| crop（居中裁剪）
+	ContentFormat  string   `json:"content_format,omitempty"`  // 正文格式：plain（默认，原样输入）| markdown（先转换为纯文本风格）
+	TitleOverflow  string   `json:"title_overflow,omitempty"`  // 标题超过20字时：error（默认）| truncate | move_to_body
+	Async          bool     `json:"async,omitempty"`           // 提交为后台任务，立即返回 job_id
+	IdempotencyKey string   `json:"idempotency_key,omitempty"` // 重试时使用同一个 key，成功过的请求直接返回第一次的结果
 }
 
 // LoginStatusResponse 登录状态响应
 type LoginStatusResponse struct {
-	IsLoggedIn bool   `json:"is_logged_in"`
-	Username   string `json:"username,omitempty"`
+	IsLoggedIn bool      `json:"is_logged_in"`
+	Username   string    `json:"username,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	RedID      string    `json:"red_id,omitempty"`
+	Avatar     string    `json:"avatar,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Cached     bool      `json:"cached"`
+}
+
+// ImportCookiesRequest 导入 cookies 请求
+type ImportCookiesRequest struct {
+	Cookies string `json:"cookies" binding:"required"`
+	Format  string `json:"format,omitempty"` // auto|netscape|json|header，默认自动识别
+}
+
+// ImportCookiesResponse 导入 cookies 响应
+type ImportCookiesResponse struct {
+	Imported    int                  `json:"imported"`
+	Format      string               `json:"format"`
+	CookiePath  string               `json:"cookie_path"`
+	LoginStatus *LoginStatusResponse `json:"login_status"`
+}
+
+// ExportCookiesResponse 导出 cookies 响应
+type ExportCookiesResponse struct {
+	Format  string `json:"format"`
+	Count   int    `json:"count"`
+	Cookies string `json:"cookies"`
 }
 
 // LoginQrcodeResponse 登录扫码二维码
@@ -50,29 +156,65 @@ type LoginQrcodeResponse struct {
 
 // PublishResponse 发布响应
 type PublishResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Images  int    `json:"images"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title           string                   `json:"title"`
+	Content         string                   `json:"content"`
+	Images          int                      `json:"images"`
+	Status          string                   `json:"status"`
+	Draft           bool                     `json:"draft,omitempty"`
+	Visibility      string                   `json:"visibility,omitempty"`
+	Location        *xiaohongshu.POI         `json:"location,omitempty"`
+	Tags            []xiaohongshu.TagResult  `json:"tags,omitempty"`
+	ImageReports    []imageprep.Report       `json:"image_reports,omitempty"`    // 每张图片上传前的预处理结果
+	TitleAdjustment *xhsutil.TitleAdjustment `json:"title_adjustment,omitempty"` // 标题超长时做的调整
+	LintWarnings    []textlint.Finding       `json:"lint_warnings,omitempty"`    // 没有阻止发布的敏感词命中
+	PostID          string                   `json:"post_id,omitempty"`
+	PostURL         string                   `json:"post_url,omitempty"`
+	XsecToken       string                   `json:"xsec_token,omitempty"`
+	NoteIDUnknown   bool                     `json:"note_id_unknown,omitempty"` // 已发布，但没能获取笔记 ID，请到创作者中心确认
+	Replayed        bool                     `json:"replayed,omitempty"`        // 相同 idempotency_key 的请求已经成功过，返回的是第一次的结果
 }
 
-// PublishVideoRequest 发布视频请求（仅支持本地单个视频文件）
+// PublishVideoRequest 发布视频请求（单个视频：本地文件或 HTTP 链接）
 type PublishVideoRequest struct {
-	Title      string   `json:"title" binding:"required"`
-	Content    string   `json:"content" binding:"required"`
-	Video      string   `json:"video" binding:"required"`
-	Tags       []string `json:"tags,omitempty"`
-	ScheduleAt string   `json:"schedule_at,omitempty"` // 定时发布时间，ISO8601格式，为空则立即发布
+	Title          string   `json:"title" binding:"required"`
+	Content        string   `json:"content" binding:"required"`
+	Video          string   `json:"video" binding:"required"` // 本地视频绝对路径或 HTTP(S) 链接
+	Tags           []string `json:"tags,omitempty"`
+	ScheduleAt     string   `json:"schedule_at,omitempty"`     // 定时发布时间，ISO8601格式，为空则立即发布
+	ScheduleMode   string   `json:"schedule_mode,omitempty"`   // 定时方式：platform（默认，1小时至14天）| local（由服务到时间发布）
+	MissedPolicy   string   `json:"missed_policy,omitempty"`   // local 定时错过时间时：run（服务恢复后立即发布，默认）| skip
+	Draft          bool     `json:"draft,omitempty"`           // 只保存到草稿箱，不发布
+	Visibility     string   `json:"visibility,omitempty"`      // 可见范围：公开（默认）/仅自己可见/仅互关好友可见
+	Location       string   `json:"location,omitempty"`        // 地点关键词，自动选择最匹配的地点
+	Mentions       []string `json:"mentions,omitempty"`        // 正文中要 @ 的用户：昵称、用户ID 或 "昵称:用户ID"
+	Cover          string   `json:"cover,omitempty"`           // 封面图片：HTTP 链接或本地绝对路径
+	CoverAt        string   `json:"cover_at,omitempty"`        // 按视频时间点截取封面，如 "3"、"2.5s"、"00:01:20"
+	ContentFormat  string   `json:"content_format,omitempty"`  // 正文格式：plain（默认，原样输入）| markdown（先转换为纯文本风格）
+	TitleOverflow  string   `json:"title_overflow,omitempty"`  // 标题超过20字时：error（默认）| truncate | move_to_body
+	Async          bool     `json:"async,omitempty"`           // 提交为后台任务，立即返回 job_id
+	IdempotencyKey string   `json:"idempotency_key,omitempty"` // 重试时使用同一个 key，成功过的请求直接返回第一次的结果
 }
 
 // PublishVideoResponse 发布视频响应
 type PublishVideoResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Video   string `json:"video"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title           string                   `json:"title"`
+	Content         string                   `json:"content"`
+	Video           string                   `json:"video"`
+	VideoInfo       *videoprobe.Info         `json:"video_info,omitempty"`       // 视频检查读取到的元数据
+	TitleAdjustment *xhsutil.TitleAdjustment `json:"title_adjustment,omitempty"` // 标题超长时做的调整
+	Cover           string                   `json:"cover,omitempty"`
+	CoverAt         string                   `json:"cover_at,omitempty"`
+	Status          string                   `json:"status"`
+	Draft           bool                     `json:"draft,omitempty"`
+	Visibility      string                   `json:"visibility,omitempty"`
+	Location        *xiaohongshu.POI         `json:"location,omitempty"`
+	Tags            []xiaohongshu.TagResult  `json:"tags,omitempty"`
+	LintWarnings    []textlint.Finding       `json:"lint_warnings,omitempty"` // 没有阻止发布的敏感词命中
+	PostID          string                   `json:"post_id,omitempty"`
+	PostURL         string                   `json:"post_url,omitempty"`
+	XsecToken       string                   `json:"xsec_token,omitempty"`
+	NoteIDUnknown   bool                     `json:"note_id_unknown,omitempty"` // 已发布，但没能获取笔记 ID，请到创作者中心确认
+	Replayed        bool                     `json:"replayed,omitempty"`        // 相同 idempotency_key 的请求已经成功过，返回的是第一次的结果
 }
 
 // FeedsListResponse Feeds列表响应
@@ -92,11 +234,119 @@ type UserProfileResponse struct {
 func (s *XiaohongshuService) DeleteCookies(ctx context.Context) error {
 	cookiePath := cookies.GetCookiesFilePath()
 	cookieLoader := cookies.NewLoadCookie(cookiePath)
+	defer s.loginCache.Invalidate()
 	return cookieLoader.DeleteCookies()
 }
 
-// CheckLoginStatus 检查登录状态
+// ImportCookies 导入已登录浏览器的 cookies，保存后重新验证登录状态
+func (s *XiaohongshuService) ImportCookies(ctx context.Context, req *ImportCookiesRequest) (*ImportCookiesResponse, error) {
+	format, err := cookies.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if format == cookies.FormatAuto {
+		format = cookies.DetectFormat(req.Cookies)
+	}
+
+	cks, err := cookies.ParseCookies(req.Cookies, format)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(cks)
+	if err != nil {
+		return nil, fmt.Errorf("序列化 cookies 失败: %w", err)
+	}
+
+	cookiePath := cookies.GetCookiesFilePath()
+	cookieLoader := cookies.NewLoadCookie(cookiePath)
+
+	// 先备份当前 cookies，新 cookies 验证不通过时恢复，避免覆盖掉仍可用的登录态
+	previous, loadErr := cookieLoader.LoadCookies()
+	hadPrevious := loadErr == nil
+
+	if err := cookieLoader.SaveCookies(data); err != nil {
+		return nil, fmt.Errorf("保存 cookies 失败: %w", err)
+	}
+	s.loginCache.Invalidate()
+	logrus.Infof("已导入 %d 个 cookies (format=%s)", len(cks), format)
+
+	status, err := s.CheckLoginStatus(ctx)
+	if err == nil && !status.IsLoggedIn {
+		err = errors.New("导入的 cookies 未处于登录状态，可能已过期")
+	}
+	if err != nil {
+		restoreErr := restoreCookies(cookieLoader, previous, hadPrevious)
+		s.loginCache.Invalidate()
+		if restoreErr != nil {
+			return nil, fmt.Errorf("验证导入的 cookies 失败: %w；恢复原 cookies 也失败: %v", err, restoreErr)
+		}
+		return nil, fmt.Errorf("验证导入的 cookies 失败，已恢复原 cookies: %w", err)
+	}
+
+	return &ImportCookiesResponse{
+		Imported:    len(cks),
+		Format:      string(format),
+		CookiePath:  cookiePath,
+		LoginStatus: status,
+	}, nil
+}
+
+// restoreCookies 把 cookies 文件恢复为导入前的内容，导入前没有 cookies 时删除文件
+func restoreCookies(loader cookies.Cookier, previous []byte, hadPrevious bool) error {
+	if !hadPrevious {
+		return loader.DeleteCookies()
+	}
+	return loader.SaveCookies(previous)
+}
+
+// ExportCookies 以指定格式导出当前保存的小红书 cookies
+func (s *XiaohongshuService) ExportCookies(ctx context.Context, formatName string) (*ExportCookiesResponse, error) {
+	format, err := cookies.ParseFormat(formatName)
+	if err != nil {
+		return nil, err
+	}
+	if format == cookies.FormatAuto {
+		format = cookies.FormatJSON
+	}
+
+	data, err := cookies.NewLoadCookie(cookies.GetCookiesFilePath()).LoadCookies()
+	if err != nil {
+		return nil, fmt.Errorf("读取 cookies 失败，请先登录: %w", err)
+	}
+
+	var cks []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cks); err != nil {
+		return nil, fmt.Errorf("解析 cookies 文件失败: %w", err)
+	}
+
+	cks = cookies.FilterXhsCookies(cks)
+	text, err := cookies.ExportCookies(cks, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportCookiesResponse{
+		Format:  string(format),
+		Count:   len(cks),
+		Cookies: text,
+	}, nil
+}
+
+// CheckLoginStatus 检查登录状态，返回当前账号的昵称、用户ID、小红书号和头像。
+// 结果会缓存一段时间，cookies 变化或检测到登录墙时自动失效。
 func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
+	if cached, ok := s.loginCache.Get(); ok {
+		return cached, nil
+	}
+
+	// 没有 cookies 文件时必然未登录，无需启动浏览器
+	if !s.loginCache.CookiesExist() {
+		response := &LoginStatusResponse{IsLoggedIn: false, CheckedAt: time.Now()}
+		s.loginCache.Set(response)
+		return response, nil
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -105,16 +355,23 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 
 	loginAction := xiaohongshu.NewLogin(page)
 
-	isLoggedIn, err := loginAction.CheckLoginStatus(ctx)
+	userInfo, err := loginAction.CheckLoginUser(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &LoginStatusResponse{
-		IsLoggedIn: isLoggedIn,
-		Username:   configs.Username,
+		IsLoggedIn: userInfo != nil,
+		CheckedAt:  time.Now(),
+	}
+	if userInfo != nil {
+		response.Username = userInfo.Nickname
+		response.UserID = userInfo.UserID
+		response.RedID = userInfo.RedID
+		response.Avatar = userInfo.Avatar
 	}
 
+	s.loginCache.Set(response)
 	return response, nil
 }
 
@@ -150,6 +407,7 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 				if er := saveCookies(page); er != nil {
 					logrus.Errorf("failed to save cookies: %v", er)
 				}
+				s.loginCache.Invalidate()
 			}
 		}()
 	}
@@ -166,18 +424,65 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 	}, nil
 }
 
-// PublishContent 发布内容
+// PublishContent 发布内容，设置了 idempotency_key 时重复的请求返回第一次的结果
 func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
-	// 验证标题长度（小红书限制：最大20个字）
-	if xhsutil.CalcTitleLength(req.Title) > 20 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	fingerprint := *req
+	fingerprint.IdempotencyKey = ""
+	resp, replayed, err := withIdempotency(ctx, s, idempotencyScopePublishContent, req.IdempotencyKey, fingerprint, func(ctx context.Context) (*PublishResponse, error) {
+		return s.publishImageNote(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Replayed = replayed
+	return resp, nil
+}
+
+func (s *XiaohongshuService) publishImageNote(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+	// 调整超长标题，按编辑器规则预检标题、正文（含标签）和图片数量，再检查敏感词
+	note, titleAdjustment, lintWarnings, err := s.prepareNote(xhsutil.Note{Title: req.Title, Content: req.Content, Tags: req.Tags, ImageCount: len(req.Images)}, req.ContentFormat, req.TitleOverflow)
+	if err != nil {
+		return nil, err
+	}
+	title, body, tags := note.Title, note.Content, note.Tags
+
+	aspect, err := imageprep.ParseAspect(req.ImageAspect)
+	if err != nil {
+		return nil, err
+	}
+	if err := imageprep.ValidateOptions(aspect, req.ImageFit); err != nil {
+		return nil, err
 	}
 
-	// 处理图片：下载URL图片或使用本地路径
+	// 处理图片：下载URL图片或使用本地路径，再统一预处理
+	xiaohongshu.ReportProgress(ctx, xiaohongshu.StepPreparing, 0, 0)
 	imagePaths, err := s.processImages(req.Images)
 	if err != nil {
 		return nil, err
 	}
+	imagePaths, imageReports, err := prepareImages(imagePaths, aspect, req.ImageFit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Draft && req.ScheduleAt != "" {
+		return nil, fmt.Errorf("保存草稿时不能设置定时发布时间")
+	}
+	if local, err := checkScheduleMode(req.ScheduleMode, req.MissedPolicy); err != nil {
+		return nil, err
+	} else if local {
+		return nil, fmt.Errorf("schedule_mode=local 需要通过任务队列提交")
+	}
+
+	visibility, err := xiaohongshu.ParseVisibility(req.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := xiaohongshu.ParseMentions(req.Mentions)
+	if err != nil {
+		return nil, err
+	}
 
 	// 解析定时发布时间
 	var scheduleTime *time.Time
@@ -193,11 +498,11 @@ func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishReq
 		maxTime := now.Add(14 * 24 * time.Hour)
 
 		if t.Before(minTime) {
-			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s",
+			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s；更早的时间可设置 schedule_mode=local 由服务定时发布",
 				t.Format("2006-01-02 15:04"), minTime.Format("2006-01-02 15:04"))
 		}
 		if t.After(maxTime) {
-			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s",
+			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s；更晚的时间可设置 schedule_mode=local 由服务定时发布",
 				t.Format("2006-01-02 15:04"), maxTime.Format("2006-01-02 15:04"))
 		}
 
@@ -207,37 +512,316 @@ func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishReq
 
 	// 构建发布内容
 	content := xiaohongshu.PublishImageContent{
-		Title:        req.Title,
-		Content:      req.Content,
-		Tags:         req.Tags,
+		Title:        title,
+		Content:      body,
+		Tags:         tags,
 		ImagePaths:   imagePaths,
 		ScheduleTime: scheduleTime,
+		Draft:        req.Draft,
+		Visibility:   visibility,
+		Location:     req.Location,
+		Mentions:     mentions,
 	}
 
 	// 执行发布
-	if err := s.publishContent(ctx, content); err != nil {
+	xiaohongshu.ReportProgress(ctx, xiaohongshu.StepOpening, 0, 0)
+	result, err := s.publishContent(ctx, content)
+	if err != nil {
 		logrus.Errorf("发布内容失败: title=%s %v", content.Title, err)
 		return nil, err
 	}
 
 	response := &PublishResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Images:  len(imagePaths),
-		Status:  "发布完成",
+		Title:           title,
+		Content:         body,
+		Images:          len(imagePaths),
+		Status:          publishStatus(req.Draft),
+		Draft:           req.Draft,
+		Visibility:      visibility,
+		Location:        result.Location,
+		Tags:            result.Tags,
+		ImageReports:    imageReports,
+		TitleAdjustment: titleAdjustment,
+		LintWarnings:    lintWarnings,
+		PostID:          result.NoteID,
+		PostURL:         result.URL,
+		XsecToken:       result.XsecToken,
+		NoteIDUnknown:   result.NoteIDUnknown,
+	}
+
+	return response, nil
+}
+
+// ValidateNoteRequest 校验笔记请求
+type ValidateNoteRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
+	Images  []string `json:"images,omitempty"` // 只统计数量，不会下载或读取
+	Video   string   `json:"video,omitempty"`  // 不为空时按视频笔记校验
+}
+
+// ValidateNote 按编辑器规则校验笔记，不打开浏览器
+func (s *XiaohongshuService) ValidateNote(req *ValidateNoteRequest) *xhsutil.NoteReport {
+	report := xhsutil.ValidateNote(xhsutil.Note{
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       req.Tags,
+		ImageCount: len(req.Images),
+		Video:      req.Video != "",
+	})
+	return &report
+}
+
+// prepareNote 按 content_format 转换正文，按 title_overflow 调整超长标题，再做发布前预检和敏感词检查，返回实际发布的笔记内容
+func (s *XiaohongshuService) prepareNote(note xhsutil.Note, contentFormat, titleOverflow string) (xhsutil.Note, *xhsutil.TitleAdjustment, []textlint.Finding, error) {
+	format, err := xhsutil.ParseContentFormat(contentFormat)
+	if err != nil {
+		return note, nil, nil, err
+	}
+	mode, err := xhsutil.ParseTitleOverflow(titleOverflow)
+	if err != nil {
+		return note, nil, nil, err
+	}
+
+	if format == xhsutil.ContentFormatMarkdown {
+		var tags []string
+		note.Content, tags = xhsutil.MarkdownToText(note.Content)
+		note.Tags = xhsutil.MergeTags(note.Tags, tags)
+	}
+	var adjustment *xhsutil.TitleAdjustment
+	note.Title, note.Content, adjustment = xhsutil.FitTitle(note.Title, note.Content, mode)
+	if adjustment != nil {
+		logrus.Infof("标题超长，已按 %s 调整: %s -> %s", mode, adjustment.OriginalTitle, note.Title)
+	}
+
+	if err := checkNote(note); err != nil {
+		return note, nil, nil, err
+	}
+	lintWarnings, err := s.checkLint(noteLintFields(note.Title, note.Content, note.Tags))
+	if err != nil {
+		return note, nil, nil, err
+	}
+	return note, adjustment, lintWarnings, nil
+}
+
+// checkNote 发布前预检，有错误时一次返回所有问题
+func checkNote(note xhsutil.Note) error {
+	report := xhsutil.ValidateNote(note)
+	if report.Valid {
+		return nil
+	}
+	return fmt.Errorf("笔记内容不符合要求: %s", strings.Join(report.Errors(), "；"))
+}
+
+// LintTextRequest 敏感词检查请求：text 用于评论等单段文本，title/content/tags 用于笔记
+type LintTextRequest struct {
+	Text    string   `json:"text,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Content string   `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// LintTextResponse 敏感词检查结果
+type LintTextResponse struct {
+	Mode      string             `json:"mode"`    // 当前配置的检查模式
+	Blocked   bool               `json:"blocked"` // 当前模式下发布是否会被阻止
+	Findings  []textlint.Finding `json:"findings"`
+	Suggested map[string]string  `json:"suggested,omitempty"` // 按替换建议改写后的文本，key 为字段名
+}
+
+// lintField 一个待检查的字段
+type lintField struct {
+	name string
+	text string
+}
+
+// noteLintFields 笔记中需要检查的字段，每个标签单独检查
+func noteLintFields(title, content string, tags []string) []lintField {
+	fields := []lintField{{"title", title}, {"content", content}}
+	for i, tag := range tags {
+		fields = append(fields, lintField{fmt.Sprintf("tags[%d]", i), tag})
 	}
+	return fields
+}
 
+// lintFields 检查所有字段，命中带上字段名
+func (s *XiaohongshuService) lintFields(fields []lintField) []textlint.Finding {
+	findings := []textlint.Finding{}
+	for _, f := range fields {
+		for _, finding := range s.linter.Lint(f.text) {
+			finding.Field = f.name
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// LintText 按当前词库检查文本，不受 off 模式影响
+func (s *XiaohongshuService) LintText(req *LintTextRequest) (*LintTextResponse, error) {
+	var fields []lintField
+	if req.Text != "" {
+		fields = append(fields, lintField{"text", req.Text})
+	}
+	if req.Title != "" || req.Content != "" || len(req.Tags) > 0 {
+		fields = append(fields, noteLintFields(req.Title, req.Content, req.Tags)...)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("text、title、content、tags 至少提供一项")
+	}
+
+	mode := configs.GetLintMode()
+	findings := s.lintFields(fields)
+	response := &LintTextResponse{
+		Mode:     mode,
+		Blocked:  mode == configs.LintModeBlock && len(textlint.Blocking(findings)) > 0,
+		Findings: findings,
+	}
+
+	for _, f := range fields {
+		var own []textlint.Finding
+		for _, finding := range findings {
+			if finding.Field == f.name {
+				own = append(own, finding)
+			}
+		}
+		if suggested := textlint.Suggest(f.text, own); suggested != f.text {
+			if response.Suggested == nil {
+				response.Suggested = make(map[string]string)
+			}
+			response.Suggested[f.name] = suggested
+		}
+	}
 	return response, nil
 }
 
-// processImages 处理图片列表，支持URL下载和本地路径
+// checkLint 发布和评论前检查敏感词：拦截模式下命中 high 级别的词返回错误，其余命中作为提示返回
+func (s *XiaohongshuService) checkLint(fields []lintField) ([]textlint.Finding, error) {
+	mode := configs.GetLintMode()
+	if mode == configs.LintModeOff {
+		return nil, nil
+	}
+
+	findings := s.lintFields(fields)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	if mode == configs.LintModeBlock {
+		if blocking := textlint.Blocking(findings); len(blocking) > 0 {
+			problems := make([]string, 0, len(blocking))
+			for _, f := range blocking {
+				problem := fmt.Sprintf("%s 中的「%s」(%s)", f.Field, f.Text, f.Category)
+				if f.Replacement != "" {
+					problem += fmt.Sprintf("，建议改为「%s」", f.Replacement)
+				}
+				problems = append(problems, problem)
+			}
+			return nil, fmt.Errorf("内容包含敏感词: %s", strings.Join(problems, "；"))
+		}
+	}
+
+	logrus.Warnf("内容命中 %d 个敏感词，继续发布", len(findings))
+	return findings, nil
+}
+
+func publishStatus(draft bool) string {
+	if draft {
+		return "已保存草稿"
+	}
+	return "发布完成"
+}
+
+// processImages 处理图片列表，支持 media_id、URL下载、本地路径、file:// 链接和 base64 图片
 func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
+	resolved := make([]string, len(images))
+	for i, image := range images {
+		if !mediastore.IsMediaID(image) {
+			resolved[i] = image
+			continue
+		}
+		path, err := s.resolveMedia(image, mediastore.KindImage)
+		if err != nil {
+			return nil, fmt.Errorf("第%d张图片: %w", i+1, err)
+		}
+		resolved[i] = path
+	}
+
 	processor := downloader.NewImageProcessor()
-	return processor.ProcessImages(images)
+	return processor.ProcessImages(resolved)
+}
+
+// SaveMedia 保存上传的图片或视频，返回可以在 images、video 中使用的 media_id
+func (s *XiaohongshuService) SaveMedia(filename string, r io.Reader) (*mediastore.Item, error) {
+	if s.media == nil {
+		return nil, fmt.Errorf("媒体上传不可用")
+	}
+	return s.media.Save(filename, r)
+}
+
+// resolveMedia 把 media_id 转换为本地文件路径，并检查媒体类型
+func (s *XiaohongshuService) resolveMedia(id, kind string) (string, error) {
+	if s.media == nil {
+		return "", fmt.Errorf("媒体上传不可用，无法使用 %s", id)
+	}
+	item, err := s.media.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if item.Kind != kind {
+		return "", fmt.Errorf("%s 是%s，不能作为%s使用", id, mediaKindName(item.Kind), mediaKindName(kind))
+	}
+	return item.Path(), nil
+}
+
+func mediaKindName(kind string) string {
+	if kind == mediastore.KindVideo {
+		return "视频"
+	}
+	return "图片"
+}
+
+// prepareImages 上传前预处理图片：转换为 JPEG、按配置缩小、调整宽高比并去除元数据
+func prepareImages(paths []string, aspect, fit string) ([]string, []imageprep.Report, error) {
+	outputs, reports, err := imageprep.ProcessAll(paths, imageprep.Options{
+		MaxEdge:   configs.GetImageMaxEdge(),
+		MaxBytes:  configs.GetImageMaxBytes(),
+		Aspect:    aspect,
+		Fit:       fit,
+		OutputDir: configs.GetPreparedImagesPath(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, r := range reports {
+		if len(r.Changes) > 0 {
+			logrus.Infof("图片预处理: %s -> %s %v", r.Source, r.Output, r.Changes)
+		}
+	}
+	return outputs, reports, nil
+}
+
+// acquirePublish 等待正在进行的发布结束，返回释放函数。
+// 同步发布不经过任务队列，与异步任务同时打开发布页面会互相干扰，所以所有发布都要先拿到它；
+// 发布草稿、编辑笔记和获取话题推荐同样会打开发布页或编辑器，通过 withPublishPage 拿到它
+func (s *XiaohongshuService) acquirePublish(ctx context.Context) (func(), error) {
+	select {
+	case s.publishSlot <- struct{}{}:
+		return func() { <-s.publishSlot }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("等待其他笔记发布完成时已取消: %w", ctx.Err())
+	}
 }
 
 // publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (*xiaohongshu.PublishResult, error) {
+	release, err := s.acquirePublish(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -246,28 +830,134 @@ func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohon
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 执行发布
 	return action.Publish(ctx, content)
 }
 
-// PublishVideo 发布视频（本地文件）
+// SuggestTopicsResponse 话题联想响应
+type SuggestTopicsResponse struct {
+	Keyword string              `json:"keyword"`
+	Topics  []xiaohongshu.Topic `json:"topics"`
+	Count   int                 `json:"count"`
+}
+
+// SuggestTopics 获取发布编辑器中关键词的话题联想，不发布笔记
+func (s *XiaohongshuService) SuggestTopics(ctx context.Context, keyword string) (*SuggestTopicsResponse, error) {
+	if strings.TrimSpace(keyword) == "" {
+		return nil, fmt.Errorf("缺少关键词")
+	}
+
+	var topics []xiaohongshu.Topic
+	err := s.withPublishPage(ctx, func(page *rod.Page) error {
+		action, err := xiaohongshu.NewPublishImageAction(page)
+		if err != nil {
+			return err
+		}
+		topics, err = action.SuggestTopics(ctx, keyword)
+		return err
+	})
+	if err != nil {
+		return nil, s.trackLoginError(err)
+	}
+
+	return &SuggestTopicsResponse{Keyword: keyword, Topics: topics, Count: len(topics)}, nil
+}
+
+// PublishVideo 发布视频（本地文件或 HTTP 链接），设置了 idempotency_key 时重复的请求返回第一次的结果
 func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
-	// 标题长度校验（小红书限制：最大20个字）
-	if xhsutil.CalcTitleLength(req.Title) > 20 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	fingerprint := *req
+	fingerprint.IdempotencyKey = ""
+	resp, replayed, err := withIdempotency(ctx, s, idempotencyScopePublishVideo, req.IdempotencyKey, fingerprint, func(ctx context.Context) (*PublishVideoResponse, error) {
+		return s.publishVideoNote(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Replayed = replayed
+	return resp, nil
+}
+
+func (s *XiaohongshuService) publishVideoNote(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
+	// 调整超长标题，按编辑器规则预检标题和正文（含标签），再检查敏感词
+	note, titleAdjustment, lintWarnings, err := s.prepareNote(xhsutil.Note{Title: req.Title, Content: req.Content, Tags: req.Tags, Video: true}, req.ContentFormat, req.TitleOverflow)
+	if err != nil {
+		return nil, err
 	}
+	title, body, tags := note.Title, note.Content, note.Tags
 
-	// 本地视频文件校验
+	// 视频文件校验：链接先下载到本地，发布结束后删除
 	if req.Video == "" {
-		return nil, fmt.Errorf("必须提供本地视频文件")
+		return nil, fmt.Errorf("必须提供视频文件")
 	}
-	if _, err := os.Stat(req.Video); err != nil {
+	xiaohongshu.ReportProgress(ctx, xiaohongshu.StepPreparing, 0, 0)
+	videoPath := req.Video
+	if mediastore.IsMediaID(req.Video) {
+		path, err := s.resolveMedia(req.Video, mediastore.KindVideo)
+		if err != nil {
+			return nil, err
+		}
+		videoPath = path
+	} else if downloader.IsVideoURL(req.Video) {
+		path, err := downloadVideo(ctx, req.Video)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(path)
+		videoPath = path
+	} else if _, err := os.Stat(videoPath); err != nil {
 		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
 	}
 
+	// 打开浏览器之前先检查视频，避免上传后长时间等待才失败
+	videoInfo, err := videoprobe.Probe(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("视频文件检查失败: %w", err)
+	}
+	if err := videoprobe.Validate(videoInfo, videoprobe.DefaultLimits); err != nil {
+		return nil, fmt.Errorf("视频不符合发布要求: %w", err)
+	}
+	logrus.Infof("视频检查通过: %s %dx%d %.1fs %s", videoInfo.Container, videoInfo.DisplayWidth, videoInfo.DisplayHeight, videoInfo.Duration, videoInfo.VideoCodec)
+
+	if req.Draft && req.ScheduleAt != "" {
+		return nil, fmt.Errorf("保存草稿时不能设置定时发布时间")
+	}
+	if local, err := checkScheduleMode(req.ScheduleMode, req.MissedPolicy); err != nil {
+		return nil, err
+	} else if local {
+		return nil, fmt.Errorf("schedule_mode=local 需要通过任务队列提交")
+	}
+
+	visibility, err := xiaohongshu.ParseVisibility(req.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := xiaohongshu.ParseMentions(req.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 封面：上传图片和按时间点截取二选一
+	if req.Cover != "" && req.CoverAt != "" {
+		return nil, fmt.Errorf("cover 和 cover_at 不能同时设置")
+	}
+	coverOffset, err := xiaohongshu.ParseCoverOffset(req.CoverAt)
+	if err != nil {
+		return nil, err
+	}
+	if coverOffset != nil && coverOffset.Seconds() > videoInfo.Duration {
+		return nil, fmt.Errorf("封面时间点 %s 超过视频时长 %.1f 秒", req.CoverAt, videoInfo.Duration)
+	}
+	var coverPath string
+	if req.Cover != "" {
+		if coverPath, err = s.prepareCover(req.Cover); err != nil {
+			return nil, err
+		}
+	}
+
 	// 解析定时发布时间
 	var scheduleTime *time.Time
 	if req.ScheduleAt != "" {
@@ -282,11 +972,11 @@ func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideo
 		maxTime := now.Add(14 * 24 * time.Hour)
 
 		if t.Before(minTime) {
-			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s",
+			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s；更早的时间可设置 schedule_mode=local 由服务定时发布",
 				t.Format("2006-01-02 15:04"), minTime.Format("2006-01-02 15:04"))
 		}
 		if t.After(maxTime) {
-			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s",
+			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s；更晚的时间可设置 schedule_mode=local 由服务定时发布",
 				t.Format("2006-01-02 15:04"), maxTime.Format("2006-01-02 15:04"))
 		}
 
@@ -296,29 +986,80 @@ func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideo
 
 	// 构建发布内容
 	content := xiaohongshu.PublishVideoContent{
-		Title:        req.Title,
-		Content:      req.Content,
-		Tags:         req.Tags,
-		VideoPath:    req.Video,
+		Title:        title,
+		Content:      body,
+		Tags:         tags,
+		VideoPath:    videoPath,
 		ScheduleTime: scheduleTime,
+		Draft:        req.Draft,
+		Visibility:   visibility,
+		Location:     req.Location,
+		Mentions:     mentions,
+		CoverPath:    coverPath,
+		CoverOffset:  coverOffset,
 	}
 
 	// 执行发布
-	if err := s.publishVideo(ctx, content); err != nil {
+	xiaohongshu.ReportProgress(ctx, xiaohongshu.StepOpening, 0, 0)
+	result, err := s.publishVideo(ctx, content)
+	if err != nil {
 		return nil, err
 	}
 
 	resp := &PublishVideoResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Video:   req.Video,
-		Status:  "发布完成",
+		Title:           title,
+		Content:         body,
+		Video:           req.Video,
+		VideoInfo:       videoInfo,
+		TitleAdjustment: titleAdjustment,
+		Cover:           req.Cover,
+		CoverAt:         req.CoverAt,
+		Status:          publishStatus(req.Draft),
+		Draft:           req.Draft,
+		Visibility:      visibility,
+		Location:        result.Location,
+		Tags:            result.Tags,
+		LintWarnings:    lintWarnings,
+		PostID:          result.NoteID,
+		PostURL:         result.URL,
+		XsecToken:       result.XsecToken,
+		NoteIDUnknown:   result.NoteIDUnknown,
 	}
 	return resp, nil
 }
 
+// downloadVideo 下载视频链接到本地，支持断点续传
+func downloadVideo(ctx context.Context, videoURL string) (string, error) {
+	logrus.Infof("下载视频: %s", videoURL)
+	d := downloader.NewVideoDownloader(configs.GetVideosPath(), configs.GetVideoDownloadMaxBytes())
+	path, err := d.DownloadVideo(ctx, videoURL)
+	if err != nil {
+		return "", fmt.Errorf("下载视频失败: %w", err)
+	}
+	return path, nil
+}
+
+// prepareCover 下载或读取封面图片，并按上传图片的规则预处理
+func (s *XiaohongshuService) prepareCover(cover string) (string, error) {
+	paths, err := s.processImages([]string{cover})
+	if err != nil {
+		return "", fmt.Errorf("处理封面图片失败: %w", err)
+	}
+	paths, _, err = prepareImages(paths, "", "")
+	if err != nil {
+		return "", fmt.Errorf("处理封面图片失败: %w", err)
+	}
+	return paths[0], nil
+}
+
 // publishVideo 执行视频发布
-func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
+func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (*xiaohongshu.PublishResult, error) {
+	release, err := s.acquirePublish(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -327,12 +1068,187 @@ func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongs
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return action.PublishVideo(ctx, content)
 }
 
+// DraftsListResponse 草稿列表响应
+type DraftsListResponse struct {
+	Drafts []xiaohongshu.DraftItem `json:"drafts"`
+	Count  int                     `json:"count"`
+}
+
+// PublishDraftRequest 发布草稿请求
+type PublishDraftRequest struct {
+	DraftID string `json:"draft_id" binding:"required"`
+}
+
+// PublishDraftResponse 发布草稿响应
+type PublishDraftResponse struct {
+	DraftID       string `json:"draft_id"`
+	Status        string `json:"status"`
+	PostID        string `json:"post_id,omitempty"`
+	PostURL       string `json:"post_url,omitempty"`
+	XsecToken     string `json:"xsec_token,omitempty"`
+	NoteIDUnknown bool   `json:"note_id_unknown,omitempty"` // 已发布，但没能获取笔记 ID，请到创作者中心确认
+}
+
+// ListDrafts 获取创作者中心草稿箱列表
+func (s *XiaohongshuService) ListDrafts(ctx context.Context) (*DraftsListResponse, error) {
+	var drafts []xiaohongshu.DraftItem
+	err := withBrowserPage(func(page *rod.Page) error {
+		var err error
+		drafts, err = xiaohongshu.NewDraftAction(page).ListDrafts(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if drafts == nil {
+		drafts = []xiaohongshu.DraftItem{}
+	}
+	return &DraftsListResponse{Drafts: drafts, Count: len(drafts)}, nil
+}
+
+// PublishDraft 发布草稿箱中的指定草稿
+func (s *XiaohongshuService) PublishDraft(ctx context.Context, draftID string) (*PublishDraftResponse, error) {
+	var result *xiaohongshu.PublishResult
+	err := s.withPublishPage(ctx, func(page *rod.Page) error {
+		var err error
+		result, err = xiaohongshu.NewDraftAction(page).PublishDraft(ctx, draftID)
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("发布草稿失败: draft_id=%s %v", draftID, err)
+		return nil, err
+	}
+
+	return &PublishDraftResponse{
+		DraftID:       draftID,
+		Status:        "发布完成",
+		PostID:        result.NoteID,
+		PostURL:       result.URL,
+		XsecToken:     result.XsecToken,
+		NoteIDUnknown: result.NoteIDUnknown,
+	}, nil
+}
+
+// DeleteDraft 删除草稿箱中的指定草稿
+func (s *XiaohongshuService) DeleteDraft(ctx context.Context, draftID string) error {
+	return withBrowserPage(func(page *rod.Page) error {
+		return xiaohongshu.NewDraftAction(page).DeleteDraft(ctx, draftID)
+	})
+}
+
+// ListMyNotes 获取创作者中心笔记管理中自己发布的笔记
+func (s *XiaohongshuService) ListMyNotes(ctx context.Context, status string, pageNum int) (*xiaohongshu.MyNotesPage, error) {
+	var result *xiaohongshu.MyNotesPage
+	err := withBrowserPage(func(page *rod.Page) error {
+		var err error
+		result, err = xiaohongshu.NewCreatorNotesAction(page).ListMyNotes(ctx, xiaohongshu.ListMyNotesOptions{
+			Status: status,
+			Page:   pageNum,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, s.trackLoginError(err)
+	}
+
+	return result, nil
+}
+
+// EditNoteRequest 编辑已发布笔记请求
+type EditNoteRequest struct {
+	NoteID     string   `json:"note_id"`
+	Title      string   `json:"title" binding:"required"`
+	Content    string   `json:"content" binding:"required"`
+	Tags       []string `json:"tags,omitempty"`
+	Images     []string `json:"images,omitempty"`      // 替换全部图片，支持 URL、本地路径、file:// 链接和 base64 图片
+	ImageOrder []int    `json:"image_order,omitempty"` // 现有图片的新顺序，如 [3,1,2]
+}
+
+// EditNoteResponse 编辑笔记响应
+type EditNoteResponse struct {
+	NoteID    string                  `json:"note_id"`
+	Title     string                  `json:"title"`
+	Status    string                  `json:"status"`
+	Tags      []xiaohongshu.TagResult `json:"tags,omitempty"`
+	PostURL   string                  `json:"post_url,omitempty"`
+	XsecToken string                  `json:"xsec_token,omitempty"`
+}
+
+// EditNote 编辑已发布的笔记
+func (s *XiaohongshuService) EditNote(ctx context.Context, req *EditNoteRequest) (*EditNoteResponse, error) {
+	if req.NoteID == "" {
+		return nil, fmt.Errorf("缺少笔记ID")
+	}
+	if xhsutil.CalcTitleLength(req.Title) > 20 {
+		return nil, fmt.Errorf("标题长度超过限制")
+	}
+	if len(req.Images) > 0 && len(req.ImageOrder) > 0 {
+		return nil, fmt.Errorf("images 和 image_order 不能同时使用")
+	}
+
+	var imagePaths []string
+	if len(req.Images) > 0 {
+		var err error
+		imagePaths, err = s.processImages(req.Images)
+		if err != nil {
+			return nil, err
+		}
+		imagePaths, _, err = prepareImages(imagePaths, "", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content := xiaohongshu.EditNoteContent{
+		NoteID:     req.NoteID,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       req.Tags,
+		ImagePaths: imagePaths,
+		ImageOrder: req.ImageOrder,
+	}
+
+	var result *xiaohongshu.PublishResult
+	err := s.withPublishPage(ctx, func(page *rod.Page) error {
+		var err error
+		result, err = xiaohongshu.NewCreatorNotesAction(page).EditNote(ctx, content)
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("编辑笔记失败: note_id=%s %v", req.NoteID, err)
+		return nil, s.trackLoginError(err)
+	}
+
+	return &EditNoteResponse{
+		NoteID:    result.NoteID,
+		Title:     req.Title,
+		Status:    "编辑完成",
+		Tags:      result.Tags,
+		PostURL:   result.URL,
+		XsecToken: result.XsecToken,
+	}, nil
+}
+
+// DeleteNote 删除自己发布的笔记
+func (s *XiaohongshuService) DeleteNote(ctx context.Context, noteID, xsecToken string) (*ActionResult, error) {
+	err := withBrowserPage(func(page *rod.Page) error {
+		return xiaohongshu.NewCreatorNotesAction(page).DeleteNote(ctx, noteID, xsecToken)
+	})
+	if err != nil {
+		logrus.Errorf("删除笔记失败: note_id=%s %v", noteID, err)
+		return nil, s.trackLoginError(err)
+	}
+
+	return &ActionResult{FeedID: noteID, Success: true, Message: "笔记已删除"}, nil
+}
+
 // ListFeeds 获取Feeds列表
 func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
 	b := newBrowser()
@@ -424,7 +1340,7 @@ func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID
 	// 获取 Feed 详情
 	result, err := action.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, config)
 	if err != nil {
-		return nil, err
+		return nil, s.trackLoginError(err)
 	}
 
 	response := &FeedDetailResponse{
@@ -459,8 +1375,30 @@ func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken
 
 }
 
-// PostCommentToFeed 发表评论到Feed
-func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
+// PostCommentToFeed 发表评论到Feed，设置了 idempotencyKey 时重复的请求返回第一次的结果
+func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string, mentionList []string, idempotencyKey string) (*PostCommentResponse, error) {
+	fingerprint := PostCommentRequest{FeedID: feedID, Content: content, Mentions: mentionList}
+	resp, replayed, err := withIdempotency(ctx, s, idempotencyScopePostComment, idempotencyKey, fingerprint, func(ctx context.Context) (*PostCommentResponse, error) {
+		return s.postComment(ctx, feedID, xsecToken, content, mentionList)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Replayed = replayed
+	return resp, nil
+}
+
+func (s *XiaohongshuService) postComment(ctx context.Context, feedID, xsecToken, content string, mentionList []string) (*PostCommentResponse, error) {
+	lintWarnings, err := s.checkLint([]lintField{{"comment", content}})
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := xiaohongshu.ParseMentions(mentionList)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -469,11 +1407,11 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
-	if err := action.PostComment(ctx, feedID, xsecToken, content); err != nil {
-		return nil, err
+	if err := action.PostComment(ctx, feedID, xsecToken, content, mentions); err != nil {
+		return nil, s.trackLoginError(err)
 	}
 
-	return &PostCommentResponse{FeedID: feedID, Success: true, Message: "评论发表成功"}, nil
+	return &PostCommentResponse{FeedID: feedID, Success: true, Message: "评论发表成功", LintWarnings: lintWarnings}, nil
 }
 
 // LikeFeed 点赞笔记
@@ -536,8 +1474,30 @@ func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecTok
 	return &ActionResult{FeedID: feedID, Success: true, Message: "取消收藏成功或未收藏"}, nil
 }
 
-// ReplyCommentToFeed 回复指定评论
-func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (*ReplyCommentResponse, error) {
+// ReplyCommentToFeed 回复指定评论，设置了 idempotencyKey 时重复的请求返回第一次的结果
+func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string, mentionList []string, idempotencyKey string) (*ReplyCommentResponse, error) {
+	fingerprint := ReplyCommentRequest{FeedID: feedID, CommentID: commentID, UserID: userID, Content: content, Mentions: mentionList}
+	resp, replayed, err := withIdempotency(ctx, s, idempotencyScopeReplyComment, idempotencyKey, fingerprint, func(ctx context.Context) (*ReplyCommentResponse, error) {
+		return s.replyComment(ctx, feedID, xsecToken, commentID, userID, content, mentionList)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Replayed = replayed
+	return resp, nil
+}
+
+func (s *XiaohongshuService) replyComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string, mentionList []string) (*ReplyCommentResponse, error) {
+	lintWarnings, err := s.checkLint([]lintField{{"comment", content}})
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := xiaohongshu.ParseMentions(mentionList)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -546,8 +1506,8 @@ func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xse
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
-	if err := action.ReplyToComment(ctx, feedID, xsecToken, commentID, userID, content); err != nil {
-		return nil, err
+	if err := action.ReplyToComment(ctx, feedID, xsecToken, commentID, userID, content, mentions); err != nil {
+		return nil, s.trackLoginError(err)
 	}
 
 	return &ReplyCommentResponse{
@@ -556,9 +1516,19 @@ func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xse
 		TargetUserID:    userID,
 		Success:         true,
 		Message:         "评论回复成功",
+		LintWarnings:    lintWarnings,
 	}, nil
 }
 
+// trackLoginError 检测到登录墙时使登录状态缓存失效，原样返回错误
+func (s *XiaohongshuService) trackLoginError(err error) error {
+	if errors.Is(err, myerrors.ErrLoginRequired) {
+		logrus.Warn("检测到登录墙，登录状态缓存已失效")
+		s.loginCache.Invalidate()
+	}
+	return err
+}
+
 func newBrowser() *headless_browser.Browser {
 	return browser.NewBrowser(configs.IsHeadless(), browser.WithBinPath(configs.GetBinPath()))
 }
@@ -589,6 +1559,17 @@ func withBrowserPage(fn func(*rod.Page) error) error {
 	return fn(page)
 }
 
+// withPublishPage 拿到发布通道后再打开浏览器页面，用于会打开发布页或编辑器的操作
+func (s *XiaohongshuService) withPublishPage(ctx context.Context, fn func(*rod.Page) error) error {
+	release, err := s.acquirePublish(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return withBrowserPage(fn)
+}
+
 // GetMyProfile 获取当前登录用户的个人信息
 func (s *XiaohongshuService) GetMyProfile(ctx context.Context) (*UserProfileResponse, error) {
 	var result *xiaohongshu.UserProfileResponse
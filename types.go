@@ -1,6 +1,9 @@
 package main
 
-import "github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+import (
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textlint"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
 
 // HTTP API 响应类型
 
@@ -67,34 +70,42 @@ type FeedDetailResponse struct {
 
 // PostCommentRequest 发表评论请求
 type PostCommentRequest struct {
-	FeedID    string `json:"feed_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
-	Content   string `json:"content" binding:"required"`
+	FeedID         string   `json:"feed_id" binding:"required"`
+	XsecToken      string   `json:"xsec_token" binding:"required"`
+	Content        string   `json:"content" binding:"required"`
+	Mentions       []string `json:"mentions,omitempty"`        // 要 @ 的用户：昵称、用户ID 或 "昵称:用户ID"
+	IdempotencyKey string   `json:"idempotency_key,omitempty"` // 重试时使用同一个 key，成功过的请求直接返回第一次的结果
 }
 
 // PostCommentResponse 发表评论响应
 type PostCommentResponse struct {
-	FeedID  string `json:"feed_id"`
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	FeedID       string             `json:"feed_id"`
+	Success      bool               `json:"success"`
+	Message      string             `json:"message"`
+	LintWarnings []textlint.Finding `json:"lint_warnings,omitempty"` // 没有阻止发布的敏感词命中
+	Replayed     bool               `json:"replayed,omitempty"`      // 相同 idempotency_key 的请求已经成功过，返回的是第一次的结果
 }
 
 // ReplyCommentRequest 回复评论请求
 type ReplyCommentRequest struct {
-	FeedID    string `json:"feed_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
-	CommentID string `json:"comment_id" binding:"required_without=UserID"`
-	UserID    string `json:"user_id" binding:"required_without=CommentID"`
-	Content   string `json:"content" binding:"required"`
+	FeedID         string   `json:"feed_id" binding:"required"`
+	XsecToken      string   `json:"xsec_token" binding:"required"`
+	CommentID      string   `json:"comment_id" binding:"required_without=UserID"`
+	UserID         string   `json:"user_id" binding:"required_without=CommentID"`
+	Content        string   `json:"content" binding:"required"`
+	Mentions       []string `json:"mentions,omitempty"`        // 要 @ 的用户：昵称、用户ID 或 "昵称:用户ID"
+	IdempotencyKey string   `json:"idempotency_key,omitempty"` // 重试时使用同一个 key，成功过的请求直接返回第一次的结果
 }
 
 // ReplyCommentResponse 回复评论响应
 type ReplyCommentResponse struct {
-	FeedID          string `json:"feed_id"`
-	TargetCommentID string `json:"target_comment_id,omitempty"`
-	TargetUserID    string `json:"target_user_id,omitempty"`
-	Success         bool   `json:"success"`
-	Message         string `json:"message"`
+	FeedID          string             `json:"feed_id"`
+	TargetCommentID string             `json:"target_comment_id,omitempty"`
+	TargetUserID    string             `json:"target_user_id,omitempty"`
+	Success         bool               `json:"success"`
+	Message         string             `json:"message"`
+	LintWarnings    []textlint.Finding `json:"lint_warnings,omitempty"` // 没有阻止发布的敏感词命中
+	Replayed        bool               `json:"replayed,omitempty"`      // 相同 idempotency_key 的请求已经成功过，返回的是第一次的结果
 }
 
 // UserProfileRequest 用户主页请求
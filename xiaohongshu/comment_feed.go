@@ -20,8 +20,8 @@ func NewCommentFeedAction(page *rod.Page) *CommentFeedAction {
 	return &CommentFeedAction{page: page}
 }
 
-// PostComment 发表评论到 Feed
-func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string) error {
+// PostComment 发表评论到 Feed，mentions 为评论中要 @ 的用户
+func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string, mentions []Mention) error {
 	// 不使用 Context(ctx)，避免继承外部 context 的超时
 	page := f.page.Timeout(60 * time.Second)
 
@@ -55,7 +55,7 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 		return fmt.Errorf("未找到评论输入区域: %w", err)
 	}
 
-	if err := elem2.Input(content); err != nil {
+	if err := inputWithMentions(elem2, content, mentions, commentMentionItemSelector); err != nil {
 		logrus.Warnf("Failed to input comment content: %v", err)
 		return fmt.Errorf("无法输入评论内容: %w", err)
 	}
@@ -79,8 +79,8 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 	return nil
 }
 
-// ReplyToComment 回复指定评论
-func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string) error {
+// ReplyToComment 回复指定评论，mentions 为回复中要 @ 的用户
+func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string, mentions []Mention) error {
 	// 增加超时时间，因为需要滚动查找评论
 	// 注意：不使用 Context(ctx)，避免继承外部 context 的超时
 	page := f.page.Timeout(5 * time.Minute)
@@ -132,7 +132,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	}
 
 	// 输入内容
-	if err := inputEl.Input(content); err != nil {
+	if err := inputWithMentions(inputEl, content, mentions, commentMentionItemSelector); err != nil {
 		return fmt.Errorf("输入回复内容失败: %w", err)
 	}
 
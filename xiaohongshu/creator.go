@@ -0,0 +1,191 @@
+package xiaohongshu
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 创作者中心通用的页面操作
+
+// clickByText 在 scope 选择器范围内查找文本匹配的可见元素并点击。
+// 优先完全匹配，其次包含匹配，返回是否点击成功。
+func clickByText(page *rod.Page, scope string, texts ...string) bool {
+	textsJSON, _ := json.Marshal(texts)
+
+	result, err := page.Eval(`(scope, texts) => {
+		const roots = Array.from(document.querySelectorAll(scope));
+		const candidates = [];
+		for (const root of roots) {
+			candidates.push(root, ...root.querySelectorAll('button, a, span, div, li'));
+		}
+		const visible = (el) => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		};
+		const wanted = JSON.parse(texts);
+		for (const exact of [true, false]) {
+			for (const text of wanted) {
+				const target = candidates.find(el => {
+					if (!visible(el)) return false;
+					const t = (el.innerText || '').trim();
+					return exact ? t === text : (t.includes(text) && t.length <= text.length + 8);
+				});
+				if (target) {
+					target.click();
+					return true;
+				}
+			}
+		}
+		return false;
+	}`, scope, string(textsJSON))
+	if err != nil || result == nil {
+		return false
+	}
+	return result.Value.Bool()
+}
+
+// confirmDialog 点击确认弹窗中的确认按钮
+func confirmDialog(page *rod.Page, texts ...string) error {
+	if len(texts) == 0 {
+		texts = []string{"确定", "确认", "删除"}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if clickByText(page, ".d-modal, .d-dialog, .el-dialog, .el-message-box, [role=dialog]", texts...) {
+			logrus.Info("已确认弹窗")
+			return nil
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	return errors.New("未找到确认弹窗")
+}
+
+// clickElementButtonJS 在元素内点击指定文本的操作按钮
+const clickElementButtonJS = `(texts) => {
+	const wanted = JSON.parse(texts);
+	const nodes = Array.from(this.querySelectorAll('button, span, div, a'));
+	for (const text of wanted) {
+		const target = nodes.find(el => (el.innerText || '').trim() === text);
+		if (target) {
+			target.click();
+			return true;
+		}
+	}
+	return false;
+}`
+
+// clickElementButton 点击卡片（草稿、笔记等）内的操作按钮，按钮需要悬停才出现时会先悬停
+func clickElementButton(item *rod.Element, texts ...string) error {
+	textsJSON, _ := json.Marshal(texts)
+
+	result, err := item.Eval(clickElementButtonJS, string(textsJSON))
+	if err != nil {
+		return errors.Wrap(err, "点击操作按钮失败")
+	}
+	if result.Value.Bool() {
+		return nil
+	}
+
+	if err := item.Hover(); err == nil {
+		time.Sleep(300 * time.Millisecond)
+		result, err = item.Eval(clickElementButtonJS, string(textsJSON))
+		if err == nil && result.Value.Bool() {
+			return nil
+		}
+	}
+
+	return errors.Errorf("没有找到[%s]按钮", strings.Join(texts, "/"))
+}
+
+// waitForText 等待页面出现包含指定文本的元素
+func waitForText(page *rod.Page, scope string, text string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		result, err := page.Eval(`(scope, text) => {
+			return Array.from(document.querySelectorAll(scope)).some(el => (el.innerText || '').includes(text));
+		}`, scope, text)
+		if err == nil && result != nil && result.Value.Bool() {
+			return true
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+	return false
+}
+
+// apiResponse 捕获到的接口响应
+type apiResponse struct {
+	URL  string
+	Body []byte
+}
+
+// apiResponseWatcher 监听页面发出的指定接口请求的响应
+type apiResponseWatcher struct {
+	C      chan apiResponse
+	cancel func()
+}
+
+// watchAPIResponses 监听 URL 包含 path 的接口响应，需要在触发请求之前调用。
+// 通道写满时丢弃新的响应，调用方用完后需要调用 Stop。
+func watchAPIResponses(page *rod.Page, method, path string) *apiResponseWatcher {
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		logrus.Warnf("启用网络监听失败: %v", err)
+	}
+
+	pp, cancel := page.WithCancel()
+	w := &apiResponseWatcher{
+		C:      make(chan apiResponse, 16),
+		cancel: cancel,
+	}
+
+	requests := make(map[proto.NetworkRequestID]string)
+	wait := pp.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			if e.Request != nil && e.Request.Method == method && strings.Contains(e.Request.URL, path) {
+				requests[e.RequestID] = e.Request.URL
+			}
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			url, ok := requests[e.RequestID]
+			if !ok {
+				return
+			}
+			delete(requests, e.RequestID)
+
+			body, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(pp)
+			if err != nil {
+				logrus.Warnf("读取接口响应失败: %v", err)
+				return
+			}
+
+			data := []byte(body.Body)
+			if body.Base64Encoded {
+				if decoded, err := base64.StdEncoding.DecodeString(body.Body); err == nil {
+					data = decoded
+				}
+			}
+
+			select {
+			case w.C <- apiResponse{URL: url, Body: data}:
+			default:
+				logrus.Warnf("接口响应过多，丢弃: %s", url)
+			}
+		},
+	)
+	go wait()
+
+	return w
+}
+
+// Stop 停止监听
+func (w *apiResponseWatcher) Stop() {
+	w.cancel()
+}
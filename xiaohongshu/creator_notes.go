@@ -0,0 +1,298 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	urlOfNoteManager = `https://creator.xiaohongshu.com/new/note-manager`
+
+	// postedNotesAPIPath 创作者中心笔记管理页加载笔记列表的接口路径
+	postedNotesAPIPath = "/creator/note/user/posted"
+
+	listMyNotesTimeout = 45 * time.Second
+)
+
+// 笔记审核状态
+const (
+	NoteStatusPublished = "已发布"
+	NoteStatusReviewing = "审核中"
+	NoteStatusRejected  = "未通过"
+)
+
+// MyNote 创作者中心笔记管理中的一篇笔记
+type MyNote struct {
+	NoteID       string `json:"note_id"`
+	Title        string `json:"title"`
+	Type         string `json:"type"` // normal（图文）| video
+	PublishTime  string `json:"publish_time"`
+	Visibility   string `json:"visibility"`
+	ReviewStatus string `json:"review_status"`
+	ReviewReason string `json:"review_reason,omitempty"`
+	ViewCount    int    `json:"view_count"`
+	LikeCount    int    `json:"like_count"`
+	CommentCount int    `json:"comment_count"`
+	CollectCount int    `json:"collect_count"`
+	ShareCount   int    `json:"share_count"`
+	Cover        string `json:"cover,omitempty"`
+	XsecToken    string `json:"xsec_token,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// MyNotesPage 笔记管理列表的一页
+type MyNotesPage struct {
+	Notes    []MyNote `json:"notes"`
+	Page     int      `json:"page"`
+	HasMore  bool     `json:"has_more"`
+	NextPage int      `json:"next_page,omitempty"`
+}
+
+// ListMyNotesOptions 笔记列表查询选项
+type ListMyNotesOptions struct {
+	Status string // all | published | reviewing | rejected，默认 all
+	Page   int    // 从 0 开始的页码
+}
+
+// noteManagerTabs 笔记管理页的 TAB，与接口的 tab 参数对应
+var noteManagerTabs = map[string]struct {
+	Tab  int
+	Text string
+}{
+	"all":       {Tab: 0, Text: "全部笔记"},
+	"published": {Tab: 1, Text: "已发布"},
+	"reviewing": {Tab: 2, Text: "审核中"},
+	"rejected":  {Tab: 3, Text: "未通过"},
+}
+
+// CreatorNotesAction 创作者中心笔记管理
+type CreatorNotesAction struct {
+	page *rod.Page
+}
+
+func NewCreatorNotesAction(page *rod.Page) *CreatorNotesAction {
+	pp := page.Timeout(120 * time.Second)
+	return &CreatorNotesAction{page: pp}
+}
+
+// ListMyNotes 获取自己发布的笔记列表（含审核状态和数据）
+func (a *CreatorNotesAction) ListMyNotes(ctx context.Context, opts ListMyNotesOptions) (*MyNotesPage, error) {
+	if opts.Status == "" {
+		opts.Status = "all"
+	}
+	tab, ok := noteManagerTabs[opts.Status]
+	if !ok {
+		return nil, errors.Errorf("不支持的笔记状态: %s（支持 all/published/reviewing/rejected）", opts.Status)
+	}
+	if opts.Page < 0 {
+		return nil, errors.New("page 不能小于 0")
+	}
+
+	page := a.page.Context(ctx)
+
+	watcher, err := openNoteManager(page, tab.Tab, tab.Text)
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+
+	deadline := time.Now().Add(listMyNotesTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case resp := <-watcher.C:
+			respTab, respPage := parsePostedNotesQuery(resp.URL)
+			if respTab != tab.Tab {
+				continue
+			}
+
+			result, err := parsePostedNotesResponse(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			result.Page = respPage
+
+			if respPage == opts.Page {
+				return result, nil
+			}
+			if respPage < opts.Page && !result.HasMore {
+				// 请求的页码超出范围
+				return &MyNotesPage{Notes: []MyNote{}, Page: opts.Page}, nil
+			}
+		case <-time.After(1500 * time.Millisecond):
+			// 滚动到底部加载下一页
+			scrollNoteListToBottom(page)
+		}
+	}
+
+	return nil, errors.New("获取笔记列表超时，请确认笔记管理页面是否可以正常打开")
+}
+
+// openNoteManager 打开笔记管理页并切换到指定 TAB，返回监听笔记列表接口的 watcher
+func openNoteManager(page *rod.Page, tab int, tabText string) (*apiResponseWatcher, error) {
+	watcher := watchAPIResponses(page, "GET", postedNotesAPIPath)
+
+	if err := page.Navigate(urlOfNoteManager); err != nil {
+		watcher.Stop()
+		return nil, errors.Wrap(err, "导航到笔记管理页面失败")
+	}
+	if err := page.WaitLoad(); err != nil {
+		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
+	}
+	time.Sleep(2 * time.Second)
+
+	if err := checkLoginWall(page); err != nil {
+		watcher.Stop()
+		return nil, err
+	}
+
+	if tab != 0 && !clickByText(page, "body", tabText) {
+		watcher.Stop()
+		return nil, errors.Errorf("没有找到笔记管理 TAB: %s", tabText)
+	}
+
+	return watcher, nil
+}
+
+func scrollNoteListToBottom(page *rod.Page) {
+	_, err := page.Eval(`() => {
+		const containers = Array.from(document.querySelectorAll('*')).filter(el => el.scrollHeight > el.clientHeight + 10 &&
+			['auto', 'scroll'].includes(getComputedStyle(el).overflowY));
+		for (const el of containers) {
+			el.scrollTop = el.scrollHeight;
+		}
+		window.scrollTo(0, document.body.scrollHeight);
+	}`)
+	if err != nil {
+		logrus.Debugf("滚动笔记列表失败: %v", err)
+	}
+}
+
+// parsePostedNotesQuery 从接口 URL 中解析 tab 和 page 参数
+func parsePostedNotesQuery(rawURL string) (tab, page int) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, 0
+	}
+	q := u.Query()
+	tab, _ = strconv.Atoi(q.Get("tab"))
+	page, _ = strconv.Atoi(q.Get("page"))
+	return tab, page
+}
+
+// postedNotesResponse 笔记管理列表接口的响应
+type postedNotesResponse struct {
+	Success bool   `json:"success"`
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Data    struct {
+		Notes []postedNote `json:"notes"`
+		Page  int          `json:"page"` // 下一页页码，-1 表示没有更多
+	} `json:"data"`
+}
+
+type postedNote struct {
+	ID             string `json:"id"`
+	DisplayTitle   string `json:"display_title"`
+	Type           string `json:"type"`
+	Time           string `json:"time"`
+	ViewCount      int    `json:"view_count"`
+	Likes          int    `json:"likes"`
+	CommentsCount  int    `json:"comments_count"`
+	CollectedCount int    `json:"collected_count"`
+	SharedCount    int    `json:"shared_count"`
+	TabStatus      int    `json:"tab_status"`
+	AuditReason    string `json:"audit_reason"`
+	PermissionCode int    `json:"permission_code"`
+	PermissionMsg  string `json:"permission_msg"`
+	XsecToken      string `json:"xsec_token"`
+	ImagesList     []struct {
+		URL string `json:"url"`
+	} `json:"images_list"`
+}
+
+// parsePostedNotesResponse 解析笔记管理列表接口的响应
+func parsePostedNotesResponse(body []byte) (*MyNotesPage, error) {
+	var resp postedNotesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "解析笔记列表失败")
+	}
+	if !resp.Success {
+		msg := resp.Msg
+		if msg == "" {
+			msg = fmt.Sprintf("code=%d", resp.Code)
+		}
+		return nil, errors.Errorf("获取笔记列表失败: %s", msg)
+	}
+
+	notes := make([]MyNote, 0, len(resp.Data.Notes))
+	for _, n := range resp.Data.Notes {
+		note := MyNote{
+			NoteID:       n.ID,
+			Title:        n.DisplayTitle,
+			Type:         n.Type,
+			PublishTime:  n.Time,
+			Visibility:   noteVisibility(n.PermissionCode, n.PermissionMsg),
+			ReviewStatus: noteReviewStatus(n.TabStatus),
+			ViewCount:    n.ViewCount,
+			LikeCount:    n.Likes,
+			CommentCount: n.CommentsCount,
+			CollectCount: n.CollectedCount,
+			ShareCount:   n.SharedCount,
+			XsecToken:    n.XsecToken,
+		}
+		if note.ReviewStatus == NoteStatusRejected {
+			note.ReviewReason = n.AuditReason
+		}
+		if len(n.ImagesList) > 0 {
+			note.Cover = n.ImagesList[0].URL
+		}
+		if n.XsecToken != "" {
+			note.URL = makeFeedDetailURL(n.ID, n.XsecToken)
+		} else if n.ID != "" {
+			note.URL = makeNoteURL(n.ID)
+		}
+		notes = append(notes, note)
+	}
+
+	result := &MyNotesPage{
+		Notes:   notes,
+		HasMore: resp.Data.Page > 0,
+	}
+	if result.HasMore {
+		result.NextPage = resp.Data.Page
+	}
+	return result, nil
+}
+
+func noteReviewStatus(tabStatus int) string {
+	switch tabStatus {
+	case 2:
+		return NoteStatusReviewing
+	case 3:
+		return NoteStatusRejected
+	default:
+		return NoteStatusPublished
+	}
+}
+
+func noteVisibility(code int, msg string) string {
+	if msg != "" {
+		return msg
+	}
+	switch code {
+	case 1:
+		return "仅自己可见"
+	case 4:
+		return "仅互关好友可见"
+	default:
+		return "公开"
+	}
+}
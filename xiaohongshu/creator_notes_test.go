@@ -0,0 +1,49 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostedNotesResponse(t *testing.T) {
+	body := `{"success":true,"code":0,"data":{"page":1,"notes":[
+		{"id":"65a1","display_title":"已发布笔记","type":"normal","time":"2024-01-20 10:30","view_count":120,"likes":8,"comments_count":2,"collected_count":3,"shared_count":1,"tab_status":1,"permission_code":0,"xsec_token":"tok","images_list":[{"url":"https://img/1.jpg"}]},
+		{"id":"65a2","display_title":"未通过笔记","type":"video","time":"2024-01-21 09:00","tab_status":3,"audit_reason":"内容违规","permission_code":1}
+	]}}`
+
+	result, err := parsePostedNotesResponse([]byte(body))
+	require.NoError(t, err)
+	require.Len(t, result.Notes, 2)
+	assert.True(t, result.HasMore)
+	assert.Equal(t, 1, result.NextPage)
+
+	first := result.Notes[0]
+	assert.Equal(t, NoteStatusPublished, first.ReviewStatus)
+	assert.Equal(t, "公开", first.Visibility)
+	assert.Equal(t, 120, first.ViewCount)
+	assert.Equal(t, "https://img/1.jpg", first.Cover)
+	assert.Contains(t, first.URL, "xsec_token=tok")
+
+	second := result.Notes[1]
+	assert.Equal(t, NoteStatusRejected, second.ReviewStatus)
+	assert.Equal(t, "内容违规", second.ReviewReason)
+	assert.Equal(t, "仅自己可见", second.Visibility)
+}
+
+func TestParsePostedNotesResponse_LastPage(t *testing.T) {
+	result, err := parsePostedNotesResponse([]byte(`{"success":true,"data":{"page":-1,"notes":[]}}`))
+	require.NoError(t, err)
+	assert.False(t, result.HasMore)
+	assert.Empty(t, result.Notes)
+
+	_, err = parsePostedNotesResponse([]byte(`{"success":false,"msg":"登录已过期"}`))
+	assert.Error(t, err)
+}
+
+func TestParsePostedNotesQuery(t *testing.T) {
+	tab, page := parsePostedNotesQuery("https://edith.xiaohongshu.com/web_api/sns/v5/creator/note/user/posted?tab=2&page=3")
+	assert.Equal(t, 2, tab)
+	assert.Equal(t, 3, page)
+}
@@ -0,0 +1,323 @@
+package xiaohongshu
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DraftItem 创作者中心草稿箱中的一条草稿
+type DraftItem struct {
+	ID        string `json:"id"`   // 页面上的草稿 ID，没有时按类型、标题和保存时间生成
+	Type      string `json:"type"` // image | video
+	Title     string `json:"title"`
+	UpdatedAt string `json:"updated_at"`
+	Cover     string `json:"cover,omitempty"`
+}
+
+// DraftAction 草稿箱操作
+type DraftAction struct {
+	page *rod.Page
+}
+
+// draftTabs 草稿箱中的分类 TAB，key 为草稿类型
+var draftTabs = []struct {
+	Type  string
+	Texts []string
+}{
+	{Type: "image", Texts: []string{"图文笔记", "图文"}},
+	{Type: "video", Texts: []string{"视频笔记", "视频"}},
+}
+
+const draftItemSelector = `.draft-item, .draft-list-item, [class*="draft-item"]`
+
+func NewDraftAction(page *rod.Page) *DraftAction {
+	pp := page.Timeout(120 * time.Second)
+	return &DraftAction{page: pp}
+}
+
+// ListDrafts 列出草稿箱中的所有草稿
+func (d *DraftAction) ListDrafts(ctx context.Context) ([]DraftItem, error) {
+	page := d.page.Context(ctx)
+
+	if err := d.openDraftBox(page); err != nil {
+		return nil, err
+	}
+
+	var drafts []DraftItem
+	for _, tab := range draftTabs {
+		if !clickByText(page, ".draft-container, .draft-box, .d-modal, .d-drawer", tab.Texts...) {
+			logrus.Debugf("草稿箱中没有找到 %s TAB", tab.Type)
+		}
+		time.Sleep(800 * time.Millisecond)
+
+		items, err := d.extractDrafts(page, tab.Type)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, items...)
+	}
+
+	return dedupeDrafts(drafts), nil
+}
+
+// PublishDraft 打开指定草稿并发布
+func (d *DraftAction) PublishDraft(ctx context.Context, draftID string) (*PublishResult, error) {
+	page := d.page.Context(ctx)
+
+	item, err := d.locateDraft(page, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记下草稿标题，发布后拿不到笔记 ID 时用来在笔记管理页查找
+	draft, err := readDraft(item, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clickElementButton(item, "编辑", "继续编辑"); err != nil {
+		return nil, err
+	}
+
+	_ = page.WaitLoad()
+	time.Sleep(2 * time.Second)
+
+	// 草稿中的视频可能需要重新处理，等待发布按钮可点击
+	btn, err := waitForPublishButtonClickable(page)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := clickPublishAndWait(page, btn)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveUnknownNoteID(ctx, d.page, result, draft.Title)
+	resolveNoteXsecToken(ctx, d.page, result)
+	return result, nil
+}
+
+// DeleteDraft 删除指定草稿
+func (d *DraftAction) DeleteDraft(ctx context.Context, draftID string) error {
+	page := d.page.Context(ctx)
+
+	item, err := d.locateDraft(page, draftID)
+	if err != nil {
+		return err
+	}
+
+	if err := clickElementButton(item, "删除"); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if err := confirmDialog(page); err != nil {
+		return err
+	}
+	time.Sleep(1 * time.Second)
+
+	// 重新打开草稿箱确认草稿已经不在列表中
+	remaining, err := d.findDrafts(page, draftID)
+	if err != nil {
+		return errors.Wrap(err, "已点击删除，但确认草稿是否删除失败")
+	}
+	if len(remaining) > 0 {
+		return errors.Errorf("删除草稿失败，草稿仍在草稿箱中: %s", draftID)
+	}
+
+	slog.Info("草稿已删除", "draft_id", draftID)
+	return nil
+}
+
+// saveDraftAndWait 点击"暂存离开"把当前编辑内容保存到草稿箱
+func saveDraftAndWait(page *rod.Page) (*PublishResult, error) {
+	if !clickByText(page, ".publish-page-publish-btn", "暂存离开", "存草稿", "保存草稿") {
+		return nil, errors.New("没有找到暂存离开按钮")
+	}
+
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		if msg := findPublishErrorToast(page); msg != "" && !strings.Contains(msg, "草稿") {
+			return nil, errors.Errorf("保存草稿失败: %s", msg)
+		}
+		if waitForText(page, ".d-toast, .d-message, [class*=\"toast\"]", "草稿", 300*time.Millisecond) {
+			slog.Info("已保存到草稿箱")
+			return &PublishResult{}, nil
+		}
+		// 保存成功后编辑器会被清空或离开
+		if has, _, err := page.Has("div.d-input input"); err == nil && !has {
+			slog.Info("已保存到草稿箱")
+			return &PublishResult{}, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrSubmitUnconfirmed, "等待保存草稿结果超时，请到草稿箱中查看")
+}
+
+func (d *DraftAction) openDraftBox(page *rod.Page) error {
+	if err := page.Navigate(urlOfPublic); err != nil {
+		return errors.Wrap(err, "导航到发布页面失败")
+	}
+	if err := page.WaitLoad(); err != nil {
+		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0.1); err != nil {
+		logrus.Warnf("等待 DOM 稳定出现问题: %v，继续尝试", err)
+	}
+
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		if clickByText(page, "body", "草稿箱") {
+			time.Sleep(1500 * time.Millisecond)
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return errors.New("没有找到草稿箱入口")
+}
+
+// draftFieldsJS 读取草稿元素上的 ID、标题、保存时间和封面
+const draftFieldsJS = `() => {
+	const text = sel => {
+		const node = this.querySelector(sel);
+		return node ? (node.innerText || '').trim() : '';
+	};
+	return JSON.stringify({
+		id: this.getAttribute('data-id') || this.getAttribute('data-draft-id') || '',
+		title: text('.title, [class*="title"]'),
+		updated_at: text('.time, [class*="time"], [class*="date"]'),
+		cover: (this.querySelector('img') || {}).src || '',
+	});
+}`
+
+func (d *DraftAction) extractDrafts(page *rod.Page, draftType string) ([]DraftItem, error) {
+	elems, err := visibleDraftElements(page)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取草稿列表失败")
+	}
+
+	items := make([]DraftItem, 0, len(elems))
+	for _, elem := range elems {
+		item, err := readDraft(elem, draftType)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// visibleDraftElements 当前 TAB 中可见的草稿元素
+func visibleDraftElements(page *rod.Page) ([]*rod.Element, error) {
+	elems, err := page.Elements(draftItemSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*rod.Element, 0, len(elems))
+	for _, elem := range elems {
+		if isElementVisible(elem) {
+			visible = append(visible, elem)
+		}
+	}
+	return visible, nil
+}
+
+// readDraft 读取一条草稿，页面上没有草稿 ID 时按内容生成，保证列表和操作时得到相同的 ID
+func readDraft(elem *rod.Element, draftType string) (DraftItem, error) {
+	result, err := elem.Eval(draftFieldsJS)
+	if err != nil {
+		return DraftItem{}, errors.Wrap(err, "读取草稿失败")
+	}
+
+	var item DraftItem
+	if err := json.Unmarshal([]byte(result.Value.String()), &item); err != nil {
+		return DraftItem{}, errors.Wrap(err, "解析草稿失败")
+	}
+	item.Type = draftType
+	if item.ID == "" {
+		item.ID = draftContentID(draftType, item.Title, item.UpdatedAt)
+	}
+	return item, nil
+}
+
+// draftContentID 按草稿类型、标题和保存时间生成 ID，不受草稿在列表中位置的影响。
+// 封面地址可能是每次打开都会变化的 blob 地址，不参与计算
+func draftContentID(draftType, title, updatedAt string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + updatedAt))
+	return draftType + "-" + hex.EncodeToString(sum[:6])
+}
+
+// locateDraft 打开草稿箱并找到指定草稿对应的元素
+func (d *DraftAction) locateDraft(page *rod.Page, draftID string) (*rod.Element, error) {
+	if strings.TrimSpace(draftID) == "" {
+		return nil, errors.New("草稿 ID 不能为空")
+	}
+
+	matches, err := d.findDrafts(page, draftID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return nil, errors.Errorf("没有找到草稿: %s，请重新获取草稿列表", draftID)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, errors.Errorf("有 %d 条草稿的标题和保存时间相同，无法区分: %s，请先在草稿箱中修改其中一条", len(matches), draftID)
+	}
+}
+
+// findDrafts 打开草稿箱，返回所有 ID 与 draftID 相同的草稿元素
+func (d *DraftAction) findDrafts(page *rod.Page, draftID string) ([]*rod.Element, error) {
+	if err := d.openDraftBox(page); err != nil {
+		return nil, err
+	}
+
+	var matches []*rod.Element
+	for _, tab := range draftTabs {
+		clickByText(page, ".draft-container, .draft-box, .d-modal, .d-drawer", tab.Texts...)
+		time.Sleep(800 * time.Millisecond)
+
+		elems, err := visibleDraftElements(page)
+		if err != nil {
+			continue
+		}
+		for _, elem := range elems {
+			item, err := readDraft(elem, tab.Type)
+			if err != nil {
+				return nil, err
+			}
+			if item.ID == draftID {
+				matches = append(matches, elem)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func dedupeDrafts(drafts []DraftItem) []DraftItem {
+	seen := make(map[string]struct{}, len(drafts))
+	result := make([]DraftItem, 0, len(drafts))
+	for _, draft := range drafts {
+		key := fmt.Sprintf("%s|%s|%s", draft.ID, draft.Title, draft.UpdatedAt)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, draft)
+	}
+	return result
+}
@@ -0,0 +1,27 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftContentID(t *testing.T) {
+	id := draftContentID("image", "周末露营", "2024-01-20 10:30")
+
+	assert.Equal(t, id, draftContentID("image", "周末露营", "2024-01-20 10:30"))
+	assert.Regexp(t, `^image-[0-9a-f]{12}$`, id)
+	assert.NotEqual(t, id, draftContentID("video", "周末露营", "2024-01-20 10:30"))
+	assert.NotEqual(t, id, draftContentID("image", "周末露营", "2024-01-21 09:00"))
+	assert.NotEqual(t, id, draftContentID("image", "周末露营2", "2024-01-20 10:30"))
+}
+
+func TestDedupeDrafts(t *testing.T) {
+	drafts := []DraftItem{
+		{ID: "image-1", Title: "a", UpdatedAt: "2024-01-01"},
+		{ID: "image-1", Title: "a", UpdatedAt: "2024-01-01"},
+		{ID: "video-1", Title: "b", UpdatedAt: "2024-01-02"},
+	}
+
+	assert.Len(t, dedupeDrafts(drafts), 2)
+}
@@ -756,6 +756,11 @@ func checkEndContainer(page *rod.Page) bool {
 func checkPageAccessible(page *rod.Page) error {
 	time.Sleep(500 * time.Millisecond)
 
+	if err := checkLoginWall(page); err != nil {
+		logrus.Warnf("检测到登录墙: %v", err)
+		return err
+	}
+
 	// 查找错误提示容器
 	wrapperEl, err := page.Timeout(2 * time.Second).Element(".access-wrapper, .error-wrapper, .not-found-wrapper, .blocked-wrapper")
 	if err != nil {
@@ -2,10 +2,13 @@ package xiaohongshu
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/pkg/errors"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 type LoginAction struct {
@@ -99,3 +102,57 @@ func (a *LoginAction) WaitForLogin(ctx context.Context) bool {
 		}
 	}
 }
+
+// CheckLoginUser 检查登录状态并从页面状态中读取当前账号信息。
+// 未登录时返回 nil, nil。
+func (a *LoginAction) CheckLoginUser(ctx context.Context) (*LoginUserInfo, error) {
+	isLoggedIn, err := a.CheckLoginStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isLoggedIn {
+		return nil, nil
+	}
+
+	pp := a.page.Context(ctx)
+
+	result, err := pp.Eval(`() => {
+		const user = (window.__INITIAL_STATE__ || {}).user || {};
+		let info = user.userInfo;
+		if (info && typeof info === 'object') {
+			if ('value' in info) info = info.value;
+			else if ('_value' in info) info = info._value;
+		}
+		return info ? JSON.stringify(info) : "";
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "read user info from __INITIAL_STATE__ failed")
+	}
+
+	userInfo := &LoginUserInfo{}
+	if raw := result.Value.String(); raw != "" {
+		if err := json.Unmarshal([]byte(raw), userInfo); err != nil {
+			return nil, errors.Wrap(err, "unmarshal user info failed")
+		}
+	}
+
+	return userInfo, nil
+}
+
+// checkLoginWall 检测页面是否弹出了登录墙（登录弹窗或被重定向到登录页）
+func checkLoginWall(page *rod.Page) error {
+	if info, err := page.Info(); err == nil && info != nil && strings.Contains(info.URL, "/login") {
+		return myerrors.ErrLoginRequired
+	}
+
+	has, elem, err := page.Has(".login-container")
+	if err != nil || !has {
+		return nil
+	}
+
+	if visible, err := elem.Visible(); err == nil && visible {
+		return myerrors.ErrLoginRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// Mention 要 @ 的用户，UserID 和 Nickname 至少提供一个
+type Mention struct {
+	UserID   string `json:"user_id,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+// mentionCandidate @ 用户选择框中的一个候选用户
+type mentionCandidate struct {
+	Name   string `json:"name"`
+	UserID string `json:"user_id"`
+}
+
+// mentionSegment 正文中的一段：普通文本或一个 @ 用户
+type mentionSegment struct {
+	Text    string
+	Mention *Mention
+}
+
+// 各页面 @ 用户选择框中的候选项
+const (
+	publishMentionItemSelector = `#creator-editor-mention-container .item, [class*="mention-container"] .item, [class*="mention"] [class*="item"]`
+	commentMentionItemSelector = `.mention-container .mention-item, [class*="mention"] [class*="item"], [class*="at-user"] [class*="item"]`
+)
+
+var userIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// ParseMention 解析 @ 用户参数，支持 "昵称"、"用户ID" 和 "昵称:用户ID" 三种写法
+func ParseMention(s string) (Mention, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "@")
+	if s == "" {
+		return Mention{}, errors.New("@ 的用户不能为空")
+	}
+
+	if i := strings.LastIndex(s, ":"); i > 0 && userIDPattern.MatchString(s[i+1:]) {
+		return Mention{Nickname: strings.TrimSpace(s[:i]), UserID: s[i+1:]}, nil
+	}
+	if userIDPattern.MatchString(s) {
+		return Mention{UserID: s}, nil
+	}
+	return Mention{Nickname: s}, nil
+}
+
+// ParseMentions 解析 @ 用户参数列表
+func ParseMentions(list []string) ([]Mention, error) {
+	mentions := make([]Mention, 0, len(list))
+	for _, s := range list {
+		m, err := ParseMention(s)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, m)
+	}
+	return mentions, nil
+}
+
+// keyword 在 @ 选择框中搜索用的关键词
+func (m Mention) keyword() string {
+	if m.Nickname != "" {
+		return m.Nickname
+	}
+	return m.UserID
+}
+
+func (m Mention) String() string {
+	if m.Nickname != "" && m.UserID != "" {
+		return fmt.Sprintf("%s(%s)", m.Nickname, m.UserID)
+	}
+	return m.keyword()
+}
+
+// splitMentionSegments 把正文拆成普通文本和 @ 用户。
+// 正文中已经写了 "@昵称" 的，在原位置插入真正的 @；其余的 @ 追加在正文末尾。
+func splitMentionSegments(content string, mentions []Mention) []mentionSegment {
+	var segments []mentionSegment
+	var trailing []Mention
+
+	type placement struct {
+		pos, end int
+		mention  *Mention
+	}
+	var placements []placement
+	for i := range mentions {
+		m := &mentions[i]
+		if m.Nickname == "" {
+			trailing = append(trailing, *m)
+			continue
+		}
+
+		found := false
+		placeholder := "@" + m.Nickname
+		for from := 0; from <= len(content); {
+			idx := strings.Index(content[from:], placeholder)
+			if idx < 0 {
+				break
+			}
+			pos := from + idx
+			overlapped := false
+			for _, p := range placements {
+				if pos < p.end && pos+len(placeholder) > p.pos {
+					overlapped = true
+					break
+				}
+			}
+			if !overlapped {
+				placements = append(placements, placement{pos: pos, end: pos + len(placeholder), mention: m})
+				found = true
+				break
+			}
+			from = pos + len(placeholder)
+		}
+		if !found {
+			trailing = append(trailing, *m)
+		}
+	}
+
+	sort.Slice(placements, func(i, j int) bool { return placements[i].pos < placements[j].pos })
+
+	last := 0
+	for _, p := range placements {
+		if p.pos > last {
+			segments = append(segments, mentionSegment{Text: content[last:p.pos]})
+		}
+		m := *p.mention
+		segments = append(segments, mentionSegment{Mention: &m})
+		last = p.end
+	}
+	if last < len(content) {
+		segments = append(segments, mentionSegment{Text: content[last:]})
+	}
+
+	for i := range trailing {
+		if i == 0 && len(segments) > 0 {
+			segments = append(segments, mentionSegment{Text: " "})
+		}
+		m := trailing[i]
+		segments = append(segments, mentionSegment{Mention: &m})
+	}
+
+	return segments
+}
+
+// inputWithMentions 输入正文，并把其中的 @ 用户通过编辑器的 @ 选择框插入
+func inputWithMentions(elem *rod.Element, content string, mentions []Mention, itemSelector string) error {
+	if len(mentions) == 0 {
+		return elem.Input(content)
+	}
+
+	for _, seg := range splitMentionSegments(content, mentions) {
+		if seg.Mention == nil {
+			if err := elem.Input(seg.Text); err != nil {
+				return errors.Wrap(err, "输入正文失败")
+			}
+			continue
+		}
+		if err := inputMention(elem, *seg.Mention, itemSelector); err != nil {
+			return errors.Wrapf(err, "@用户[%s]失败", seg.Mention)
+		}
+	}
+	return nil
+}
+
+// inputMention 与 inputTag 相同，逐字输入 "@关键词" 触发选择框，再点击匹配的用户
+func inputMention(elem *rod.Element, m Mention, itemSelector string) error {
+	if err := elem.Input("@"); err != nil {
+		return errors.Wrap(err, "输入@失败")
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	for _, char := range m.keyword() {
+		if err := elem.Input(string(char)); err != nil {
+			return errors.Wrapf(err, "输入字符[%c]失败", char)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	page := elem.Page()
+	candidates, err := waitForMentionCandidates(page, itemSelector, 8*time.Second)
+	if err != nil {
+		return err
+	}
+
+	index, ok := chooseMentionCandidate(m, candidates)
+	if !ok {
+		names := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			names = append(names, c.Name)
+		}
+		return errors.Errorf("没有找到匹配的用户，候选用户: %s", strings.Join(names, "、"))
+	}
+
+	result, err := page.Eval(`(selector, index) => {
+		const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		});
+		if (index >= items.length) return false;
+		items[index].click();
+		return true;
+	}`, itemSelector, index)
+	if err != nil || !result.Value.Bool() {
+		return errors.New("点击用户选项失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	slog.Info("成功@用户", "mention", m.String(), "selected", candidates[index].Name)
+	return nil
+}
+
+// waitForMentionCandidates 等待 @ 选择框中出现候选用户
+func waitForMentionCandidates(page *rod.Page, itemSelector string, timeout time.Duration) ([]mentionCandidate, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(800 * time.Millisecond)
+
+		result, err := page.Eval(`(selector) => {
+			const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+				const rect = el.getBoundingClientRect();
+				return rect.width > 0 && rect.height > 0;
+			});
+			return JSON.stringify(items.map(el => {
+				const lines = (el.innerText || '').split('\n').map(s => s.trim()).filter(Boolean);
+				const id = el.outerHTML.match(/[0-9a-f]{24}/);
+				return {name: lines[0] || '', user_id: id ? id[0] : ''};
+			}));
+		}`, itemSelector)
+		if err != nil {
+			continue
+		}
+
+		var candidates []mentionCandidate
+		if err := json.Unmarshal([]byte(result.Value.String()), &candidates); err != nil {
+			continue
+		}
+		if len(candidates) > 0 {
+			return candidates, nil
+		}
+	}
+
+	return nil, errors.New("没有出现@用户选择框")
+}
+
+// chooseMentionCandidate 优先按用户 ID 匹配，其次按昵称完全匹配
+func chooseMentionCandidate(m Mention, candidates []mentionCandidate) (int, bool) {
+	if m.UserID != "" {
+		for i, c := range candidates {
+			if c.UserID == m.UserID {
+				return i, true
+			}
+		}
+	}
+
+	if m.Nickname != "" {
+		for i, c := range candidates {
+			if strings.EqualFold(strings.TrimSpace(c.Name), m.Nickname) {
+				return i, true
+			}
+		}
+	}
+
+	return -1, false
+}
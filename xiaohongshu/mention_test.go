@@ -0,0 +1,82 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserID = "5f1c2b3a4d5e6f7a8b9c0d1e"
+
+func TestParseMention(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Mention
+	}{
+		{"小红薯", Mention{Nickname: "小红薯"}},
+		{"@小红薯 ", Mention{Nickname: "小红薯"}},
+		{testUserID, Mention{UserID: testUserID}},
+		{"小红薯:" + testUserID, Mention{Nickname: "小红薯", UserID: testUserID}},
+		{"a:b", Mention{Nickname: "a:b"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMention(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	_, err := ParseMention(" @ ")
+	assert.Error(t, err)
+}
+
+func TestSplitMentionSegments(t *testing.T) {
+	mentions := []Mention{
+		{Nickname: "小明"},
+		{UserID: testUserID},
+		{Nickname: "小红"},
+		{Nickname: "阿花"},
+	}
+
+	segments := splitMentionSegments("和@小红、@小明一起去玩", mentions)
+
+	var texts []string
+	var order []string
+	for _, seg := range segments {
+		if seg.Mention == nil {
+			texts = append(texts, seg.Text)
+			continue
+		}
+		order = append(order, seg.Mention.keyword())
+	}
+
+	assert.Equal(t, []string{"和", "、", "一起去玩", " "}, texts)
+	assert.Equal(t, []string{"小红", "小明", testUserID, "阿花"}, order)
+}
+
+func TestSplitMentionSegmentsRepeatedNickname(t *testing.T) {
+	segments := splitMentionSegments("@小明 @小明", []Mention{{Nickname: "小明"}, {Nickname: "小明"}})
+
+	require.Len(t, segments, 3)
+	assert.NotNil(t, segments[0].Mention)
+	assert.Equal(t, " ", segments[1].Text)
+	assert.NotNil(t, segments[2].Mention)
+}
+
+func TestChooseMentionCandidate(t *testing.T) {
+	candidates := []mentionCandidate{
+		{Name: "小明同学", UserID: "aaaaaaaaaaaaaaaaaaaaaaaa"},
+		{Name: "小明", UserID: testUserID},
+	}
+
+	index, ok := chooseMentionCandidate(Mention{Nickname: "小明"}, candidates)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+
+	index, ok = chooseMentionCandidate(Mention{Nickname: "小明同学", UserID: testUserID}, candidates)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index, "用户 ID 优先于昵称")
+
+	_, ok = chooseMentionCandidate(Mention{Nickname: "小"}, candidates)
+	assert.False(t, ok)
+}
@@ -0,0 +1,245 @@
+package xiaohongshu
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// errNoteNotInManager 笔记管理页中找不到指定笔记
+var errNoteNotInManager = errors.New("笔记管理中没有找到该笔记")
+
+// errNoteCardNotFound 笔记管理页中没有带笔记 ID 的卡片，无法确定要操作哪一张卡片
+var errNoteCardNotFound = errors.New("笔记管理页中没有找到带笔记 ID 的卡片")
+
+// noteCardSelector 笔记管理页中的笔记卡片
+const noteCardSelector = `.note, .note-item, [class*="note-item"], [class*="noteItem"]`
+
+// DeleteNote 删除自己发布的笔记。
+// 优先在创作者中心笔记管理页中删除，找不到时通过笔记详情页的"更多"菜单删除（需要 xsecToken），
+// 删除后会重新检查笔记管理列表，确认笔记已经不存在。
+func (a *CreatorNotesAction) DeleteNote(ctx context.Context, noteID, xsecToken string) error {
+	noteID = strings.TrimSpace(noteID)
+	if noteID == "" {
+		return errors.New("笔记 ID 不能为空")
+	}
+
+	page := a.page.Context(ctx)
+
+	err := deleteNoteInManager(page, noteID)
+	if errors.Is(err, errNoteNotInManager) || errors.Is(err, errNoteCardNotFound) {
+		if xsecToken == "" {
+			return errors.Wrap(err, "无法在笔记管理中删除，请提供 xsec_token 通过笔记详情页删除")
+		}
+		logrus.Infof("笔记管理中无法删除笔记 %s（%v），尝试通过笔记详情页删除", noteID, err)
+		return deleteNoteViaDetail(page, noteID, xsecToken)
+	}
+	if err != nil {
+		return err
+	}
+
+	time.Sleep(2 * time.Second)
+
+	// 确认笔记已被删除，无法确认时不能当作删除成功
+	err = findNoteInManager(page, noteID)
+	if err == nil {
+		return errors.Errorf("删除后笔记 %s 仍然存在，请到创作者中心确认", noteID)
+	}
+	if !errors.Is(err, errNoteNotInManager) {
+		return errors.Wrapf(err, "已执行删除，但无法确认笔记 %s 是否已删除，请到创作者中心确认", noteID)
+	}
+
+	slog.Info("笔记已删除", "note_id", noteID)
+	return nil
+}
+
+// deleteNoteViaDetail 通过笔记详情页删除，再重新打开详情页，出现笔记已删除的提示才算删除成功。
+// 这条路径上的笔记本来就不在笔记管理列表中，不能用笔记管理列表确认
+func deleteNoteViaDetail(page *rod.Page, noteID, xsecToken string) error {
+	if err := deleteNoteInDetail(page, noteID, xsecToken); err != nil {
+		return err
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if err := checkNoteRemovedInDetail(page, noteID, xsecToken); err != nil {
+		return errors.Wrapf(err, "已通过详情页删除，但无法确认笔记 %s 是否已删除，请到创作者中心确认", noteID)
+	}
+
+	slog.Info("笔记已删除", "note_id", noteID)
+	return nil
+}
+
+func deleteNoteInManager(page *rod.Page, noteID string) error {
+	if err := findNoteInManager(page, noteID); err != nil {
+		return err
+	}
+
+	card, err := locateNoteCard(page, noteID)
+	if err != nil {
+		return err
+	}
+
+	if err := clickElementButton(card, "删除"); err != nil {
+		return errors.Wrap(err, "点击笔记删除按钮失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	return confirmDialog(page)
+}
+
+// findNoteInManager 打开笔记管理页并逐页加载，直到"全部笔记"列表中出现该笔记
+func findNoteInManager(page *rod.Page, noteID string) error {
+	watcher, err := openNoteManager(page, 0, "")
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	deadline := time.Now().Add(listMyNotesTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case resp := <-watcher.C:
+			if tab, _ := parsePostedNotesQuery(resp.URL); tab != 0 {
+				continue
+			}
+
+			result, err := parsePostedNotesResponse(resp.Body)
+			if err != nil {
+				return err
+			}
+			for _, note := range result.Notes {
+				if note.NoteID == noteID {
+					return nil
+				}
+			}
+
+			if !result.HasMore {
+				return errNoteNotInManager
+			}
+		case <-time.After(1500 * time.Millisecond):
+			scrollNoteListToBottom(page)
+		}
+	}
+
+	return errors.New("在笔记管理中查找笔记超时")
+}
+
+// locateNoteCard 找到笔记对应的卡片。只认卡片中链接或 data-* 属性带有笔记 ID 的卡片，
+// 不按列表位置猜测，避免列表变化后操作到别的笔记
+func locateNoteCard(page *rod.Page, noteID string) (*rod.Element, error) {
+	result, err := page.Eval(`(selector, noteID) => {
+		const cards = Array.from(document.querySelectorAll(selector)).filter(el => {
+			const rect = el.getBoundingClientRect();
+			if (rect.width === 0 || rect.height === 0) return false;
+			// 只保留最外层的卡片
+			return !el.parentElement || !el.parentElement.closest(selector);
+		});
+		const carriesID = card => [card, ...card.querySelectorAll('*')].some(node =>
+			(node.getAttribute('href') || '').includes(noteID) ||
+			Array.from(node.attributes).some(attr => attr.name.startsWith('data-') && attr.value.includes(noteID)));
+		const matched = cards.filter(carriesID);
+		if (matched.length !== 1) return matched.length;
+		document.querySelectorAll('[data-xhs-mcp-target]').forEach(el => el.removeAttribute('data-xhs-mcp-target'));
+		matched[0].setAttribute('data-xhs-mcp-target', noteID);
+		matched[0].scrollIntoView({block: 'center'});
+		return 1;
+	}`, noteCardSelector, noteID)
+	if err != nil {
+		return nil, errors.Wrap(err, "查找笔记卡片失败")
+	}
+	if n := result.Value.Int(); n == 0 {
+		return nil, errNoteCardNotFound
+	} else if n > 1 {
+		return nil, errors.Errorf("笔记管理页中有 %d 张卡片带有笔记 ID %s，无法确定要操作的笔记", n, noteID)
+	}
+
+	card, err := page.Element(`[data-xhs-mcp-target]`)
+	if err != nil {
+		return nil, errors.Wrap(err, "查找笔记卡片失败")
+	}
+	return card, nil
+}
+
+// deleteNoteInDetail 通过笔记详情页的"更多"菜单删除笔记
+func deleteNoteInDetail(page *rod.Page, noteID, xsecToken string) error {
+	if err := page.Navigate(makeFeedDetailURL(noteID, xsecToken)); err != nil {
+		return errors.Wrap(err, "打开笔记详情页失败")
+	}
+	if err := page.WaitLoad(); err != nil {
+		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
+	}
+	time.Sleep(2 * time.Second)
+
+	if err := checkPageAccessible(page); err != nil {
+		return err
+	}
+
+	more, err := page.Timeout(10 * time.Second).Element(`.note-container .more-icon, .note-container [class*="more"], .author-wrapper [class*="more"]`)
+	if err != nil {
+		return errors.Wrap(err, "没有找到笔记的更多菜单")
+	}
+	if err := more.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "点击更多菜单失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if !clickByText(page, ".d-popover, .dropdown, [class*=menu], [class*=popover]", "删除笔记", "删除") {
+		return errors.New("更多菜单中没有删除选项，只能删除自己的笔记")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	return confirmDialog(page)
+}
+
+// noteRemovedKeywords 笔记详情页上表示笔记已经不存在的提示
+var noteRemovedKeywords = []string{
+	"该笔记已被删除",
+	"笔记不存在",
+	"内容不存在",
+	"已失效",
+}
+
+// checkNoteRemovedInDetail 重新打开笔记详情页，页面显示笔记不存在或已删除时返回 nil
+func checkNoteRemovedInDetail(page *rod.Page, noteID, xsecToken string) error {
+	if err := page.Navigate(makeFeedDetailURL(noteID, xsecToken)); err != nil {
+		return errors.Wrap(err, "重新打开笔记详情页失败")
+	}
+	if err := page.WaitLoad(); err != nil {
+		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
+	}
+	time.Sleep(2 * time.Second)
+
+	if err := checkLoginWall(page); err != nil {
+		return err
+	}
+
+	wrapper, err := page.Timeout(5 * time.Second).Element(".access-wrapper, .error-wrapper, .not-found-wrapper")
+	if err != nil {
+		return errors.New("笔记详情页仍可以正常打开")
+	}
+	text, err := wrapper.Text()
+	if err != nil {
+		return errors.Wrap(err, "读取笔记详情页提示失败")
+	}
+	if !isNoteRemovedText(text) {
+		return errors.Errorf("笔记详情页的提示不是笔记已删除: %s", strings.TrimSpace(text))
+	}
+	return nil
+}
+
+// isNoteRemovedText 页面提示是否表示笔记已经不存在
+func isNoteRemovedText(text string) bool {
+	for _, kw := range noteRemovedKeywords {
+		if strings.Contains(text, kw) {
+			return true
+		}
+	}
+	return false
+}
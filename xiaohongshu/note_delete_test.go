@@ -0,0 +1,16 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNoteRemovedText(t *testing.T) {
+	assert.True(t, isNoteRemovedText("抱歉，该笔记已被删除"))
+	assert.True(t, isNoteRemovedText("你访问的笔记不存在\n返回首页"))
+	// 审核中或被限流的笔记也会暂时无法浏览，不能当作已删除
+	assert.False(t, isNoteRemovedText("当前笔记暂时无法浏览"))
+	assert.False(t, isNoteRemovedText("私密笔记，仅作者可见"))
+	assert.False(t, isNoteRemovedText(""))
+}
@@ -0,0 +1,328 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	urlOfNoteUpdate = `https://creator.xiaohongshu.com/publish/update?id=%s`
+
+	imagePreviewSelector = ".img-preview-area .pr"
+)
+
+// EditNoteContent 编辑已发布笔记的内容
+type EditNoteContent struct {
+	NoteID     string
+	Title      string
+	Content    string   // 替换整个正文（包括原有的话题标签）
+	Tags       []string // 追加在新正文之后的话题标签
+	ImagePaths []string // 非空时替换全部图片
+	ImageOrder []int    // 现有图片的新顺序（从 1 开始的原位置），与 ImagePaths 互斥
+}
+
+// imageMove 一次图片拖动：把 From 位置的图片移到 To 位置（从 0 开始）
+type imageMove struct {
+	From int
+	To   int
+}
+
+// EditNote 在创作者中心编辑器中打开已发布的笔记，替换标题、正文和标签，可选调整或替换图片
+func (a *CreatorNotesAction) EditNote(ctx context.Context, content EditNoteContent) (*PublishResult, error) {
+	if content.NoteID == "" {
+		return nil, errors.New("笔记 ID 不能为空")
+	}
+	if len(content.ImagePaths) > 0 && len(content.ImageOrder) > 0 {
+		return nil, errors.New("不能同时替换图片和调整图片顺序")
+	}
+
+	page := a.page.Context(ctx)
+
+	if err := openNoteEditor(page, content.NoteID); err != nil {
+		return nil, err
+	}
+
+	if len(content.ImagePaths) > 0 {
+		if err := replaceImages(page, content.ImagePaths); err != nil {
+			return nil, errors.Wrap(err, "替换图片失败")
+		}
+	} else if len(content.ImageOrder) > 0 {
+		if err := reorderImages(page, content.ImageOrder); err != nil {
+			return nil, errors.Wrap(err, "调整图片顺序失败")
+		}
+	}
+
+	tagResults, err := fillNoteForm(page, content.Title, content.Content, content.Tags, nil, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书编辑笔记失败")
+	}
+
+	submitButton, err := page.Element(".publish-page-publish-btn button.bg-red")
+	if err != nil {
+		return nil, errors.Wrap(err, "查找发布按钮失败")
+	}
+
+	result, err := clickPublishAndWait(page, submitButton)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书编辑笔记失败")
+	}
+	result.Tags = tagResults
+	if result.NoteID == "" {
+		result.NoteID = content.NoteID
+		result.URL = makeNoteURL(content.NoteID)
+		result.NoteIDUnknown = false
+	}
+
+	resolveNoteXsecToken(ctx, a.page, result)
+	return result, nil
+}
+
+// openNoteEditor 打开笔记的编辑器：优先在笔记管理页点击带笔记 ID 的卡片上的"编辑"，
+// 不行时直接打开编辑地址。修改任何内容之前确认编辑器打开的是该笔记
+func openNoteEditor(page *rod.Page, noteID string) error {
+	err := findNoteInManager(page, noteID)
+	if errors.Is(err, errNoteNotInManager) {
+		return errors.Errorf("笔记管理中没有找到笔记 %s，只能编辑自己发布的笔记", noteID)
+	}
+	if err != nil {
+		return err
+	}
+
+	opened := false
+	if card, err := locateNoteCard(page, noteID); err != nil {
+		logrus.Warnf("笔记管理页中无法定位笔记卡片: %v", err)
+	} else if err := clickElementButton(card, "编辑"); err != nil {
+		logrus.Warnf("点击笔记编辑按钮失败: %v", err)
+	} else {
+		opened = waitForNoteEditor(page, 15*time.Second) && isNoteEditorFor(page, noteID)
+	}
+
+	if !opened {
+		logrus.Info("直接打开笔记编辑页面")
+		if err := page.Navigate(fmt.Sprintf(urlOfNoteUpdate, noteID)); err != nil {
+			return errors.Wrap(err, "打开笔记编辑页面失败")
+		}
+		if !waitForNoteEditor(page, 30*time.Second) {
+			return errors.New("打开笔记编辑器超时")
+		}
+	}
+
+	// 等待编辑器加载原有内容
+	time.Sleep(2 * time.Second)
+
+	if !isNoteEditorFor(page, noteID) {
+		return errors.Errorf("打开的编辑器不是笔记 %s 的编辑页面，没有修改任何内容", noteID)
+	}
+	return nil
+}
+
+// isNoteEditorFor 当前页面是否为该笔记的编辑页面
+func isNoteEditorFor(page *rod.Page, noteID string) bool {
+	info, err := page.Info()
+	if err != nil {
+		return false
+	}
+	return editorURLMatchesNote(info.URL, noteID)
+}
+
+// editorURLMatchesNote 编辑页地址中的笔记 ID 是否为 noteID
+func editorURLMatchesNote(rawURL, noteID string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(u.Path, "/publish/update") && u.Query().Get("id") == noteID
+}
+
+func waitForNoteEditor(page *rod.Page, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if has, _, err := page.Has("div.d-input input"); err == nil && has {
+			return true
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return false
+}
+
+// replaceImages 用新图片替换笔记的全部图片。
+// 笔记至少需要保留一张图片，所以先删到只剩一张，上传新图片后再删除最后一张旧图。
+func replaceImages(page *rod.Page, imagePaths []string) error {
+	previews, err := page.Elements(imagePreviewSelector)
+	if err != nil {
+		return errors.Wrap(err, "获取已有图片失败")
+	}
+	existing := len(previews)
+
+	for i := 0; i < existing-1; i++ {
+		if err := removeImageAt(page, 0); err != nil {
+			return err
+		}
+	}
+
+	kept := existing
+	if kept > 1 {
+		kept = 1
+	}
+	if err := appendImages(page, imagePaths, kept); err != nil {
+		return err
+	}
+
+	if kept == 1 {
+		if err := removeImageAt(page, 0); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("图片替换完成", "removed", existing, "uploaded", len(imagePaths))
+	return nil
+}
+
+// removeImageAt 删除指定位置的图片，并等待图片数量减少
+func removeImageAt(page *rod.Page, index int) error {
+	previews, err := page.Elements(imagePreviewSelector)
+	if err != nil || index >= len(previews) {
+		return errors.Errorf("没有找到第%d张图片", index+1)
+	}
+	before := len(previews)
+
+	preview := previews[index]
+	if err := preview.Hover(); err != nil {
+		return errors.Wrap(err, "悬停图片失败")
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	result, err := preview.Eval(`() => {
+		const btn = this.querySelector('.close, .delete, [class*="close"], [class*="delete"]');
+		if (!btn) return false;
+		btn.click();
+		return true;
+	}`)
+	if err != nil || !result.Value.Bool() {
+		return errors.Errorf("没有找到第%d张图片的删除按钮", index+1)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if elems, err := page.Elements(imagePreviewSelector); err == nil && len(elems) < before {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return errors.Errorf("删除第%d张图片失败", index+1)
+}
+
+// reorderImages 按 order 拖动图片调整顺序
+func reorderImages(page *rod.Page, order []int) error {
+	previews, err := page.Elements(imagePreviewSelector)
+	if err != nil {
+		return errors.Wrap(err, "获取已有图片失败")
+	}
+
+	moves, err := planImageMoves(order, len(previews))
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		if err := dragImage(page, move.From, move.To); err != nil {
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	slog.Info("图片顺序调整完成", "order", order)
+	return nil
+}
+
+// planImageMoves 计算把图片调整为 order 顺序需要的拖动步骤。
+// order 是从 1 开始的原位置列表，必须是 1..count 的一个排列。
+func planImageMoves(order []int, count int) ([]imageMove, error) {
+	if len(order) != count {
+		return nil, errors.Errorf("图片顺序需要包含全部%d张图片，当前为%d张", count, len(order))
+	}
+
+	seen := make(map[int]bool, count)
+	for _, pos := range order {
+		if pos < 1 || pos > count || seen[pos] {
+			return nil, errors.Errorf("图片顺序无效: %v，需要是 1 到 %d 的排列", order, count)
+		}
+		seen[pos] = true
+	}
+
+	current := make([]int, count)
+	for i := range current {
+		current[i] = i + 1
+	}
+
+	var moves []imageMove
+	for to, want := range order {
+		from := to
+		for current[from] != want {
+			from++
+		}
+		if from == to {
+			continue
+		}
+
+		moves = append(moves, imageMove{From: from, To: to})
+		item := current[from]
+		copy(current[to+1:from+1], current[to:from])
+		current[to] = item
+	}
+
+	return moves, nil
+}
+
+// dragImage 用鼠标把 from 位置的图片拖到 to 位置
+func dragImage(page *rod.Page, from, to int) error {
+	previews, err := page.Elements(imagePreviewSelector)
+	if err != nil || from >= len(previews) || to >= len(previews) {
+		return errors.Errorf("拖动图片失败: 第%d张 -> 第%d张", from+1, to+1)
+	}
+
+	start, err := elementCenter(previews[from])
+	if err != nil {
+		return err
+	}
+	end, err := elementCenter(previews[to])
+	if err != nil {
+		return err
+	}
+
+	mouse := page.Mouse
+	if err := mouse.MoveTo(start); err != nil {
+		return errors.Wrap(err, "移动鼠标失败")
+	}
+	if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "按下鼠标失败")
+	}
+	if err := mouse.MoveLinear(end, 20); err != nil {
+		return errors.Wrap(err, "拖动图片失败")
+	}
+	time.Sleep(200 * time.Millisecond)
+	if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "松开鼠标失败")
+	}
+
+	logrus.Infof("已拖动图片: 第%d张 -> 第%d张", from+1, to+1)
+	return nil
+}
+
+func elementCenter(elem *rod.Element) (proto.Point, error) {
+	shape, err := elem.Shape()
+	if err != nil || shape == nil || len(shape.Quads) == 0 {
+		return proto.Point{}, errors.New("获取图片位置失败")
+	}
+	box := shape.Box()
+	return proto.Point{X: box.X + box.Width/2, Y: box.Y + box.Height/2}, nil
+}
@@ -0,0 +1,63 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applyImageMoves 按拖动步骤模拟图片列表的变化
+func applyImageMoves(count int, moves []imageMove) []int {
+	list := make([]int, count)
+	for i := range list {
+		list[i] = i + 1
+	}
+	for _, m := range moves {
+		item := list[m.From]
+		list = append(list[:m.From], list[m.From+1:]...)
+		list = append(list[:m.To], append([]int{item}, list[m.To:]...)...)
+	}
+	return list
+}
+
+func TestPlanImageMoves(t *testing.T) {
+	orders := [][]int{
+		{1, 2, 3},
+		{3, 1, 2},
+		{2, 3, 1},
+		{4, 3, 2, 1},
+		{1, 4, 2, 5, 3},
+	}
+
+	for _, order := range orders {
+		moves, err := planImageMoves(order, len(order))
+		require.NoError(t, err)
+		assert.Equal(t, order, applyImageMoves(len(order), moves))
+	}
+
+	moves, err := planImageMoves([]int{1, 2, 3}, 3)
+	require.NoError(t, err)
+	assert.Empty(t, moves)
+}
+
+func TestPlanImageMoves_Invalid(t *testing.T) {
+	_, err := planImageMoves([]int{1, 2}, 3)
+	assert.Error(t, err)
+
+	_, err = planImageMoves([]int{1, 1, 2}, 3)
+	assert.Error(t, err)
+
+	_, err = planImageMoves([]int{0, 1, 2}, 3)
+	assert.Error(t, err)
+}
+
+func TestEditorURLMatchesNote(t *testing.T) {
+	noteID := "64f1a2b3c4d5e6f7a8b9c0d1"
+
+	assert.True(t, editorURLMatchesNote("https://creator.xiaohongshu.com/publish/update?id="+noteID, noteID))
+	assert.True(t, editorURLMatchesNote("https://creator.xiaohongshu.com/publish/update?id="+noteID+"&source=official", noteID))
+	assert.False(t, editorURLMatchesNote("https://creator.xiaohongshu.com/publish/update?id=65a1b2c3000000001e03a4b5", noteID))
+	assert.False(t, editorURLMatchesNote("https://creator.xiaohongshu.com/publish/publish?source=official", noteID))
+	assert.False(t, editorURLMatchesNote("https://creator.xiaohongshu.com/new/note-manager?id="+noteID, noteID))
+}
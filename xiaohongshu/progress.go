@@ -0,0 +1,34 @@
+package xiaohongshu
+
+import "context"
+
+// 发布过程的步骤
+const (
+	StepPreparing    = "preparing"     // 下载和预处理图片、检查视频
+	StepOpening      = "opening"       // 打开发布页面
+	StepUploading    = "uploading"     // 上传图片或视频，current/total 为第几个
+	StepSettingCover = "setting_cover" // 设置视频封面
+	StepFillingForm  = "filling_form"  // 填写标题、正文、标签和发布设置
+	StepProcessing   = "processing"    // 等待视频处理完成
+	StepSubmitting   = "submitting"    // 点击发布并等待结果
+)
+
+// ProgressFunc 接收发布进度，total 为 0 表示该步骤没有计数
+type ProgressFunc func(step string, current, total int)
+
+type progressKey struct{}
+
+// WithProgress 返回带有进度回调的 context，发布过程中的每个步骤都会回调
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress 报告当前步骤，ctx 中没有进度回调时什么都不做
+func ReportProgress(ctx context.Context, step string, current, total int) {
+	if ctx == nil {
+		return
+	}
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(step, current, total)
+	}
+}
@@ -22,6 +22,19 @@ type PublishImageContent struct {
 	Tags         []string
 	ImagePaths   []string
 	ScheduleTime *time.Time // 定时发布时间，nil 表示立即发布
+	Draft        bool       // 只保存到草稿箱，不发布
+	Visibility   string     // 可见范围，空表示公开
+	Location     string     // 地点关键词，为空不添加地点
+	Mentions     []Mention  // 正文中要 @ 的用户
+}
+
+// publishOptions 填写完标题正文后的发布选项
+type publishOptions struct {
+	ScheduleTime *time.Time
+	Draft        bool
+	Visibility   string
+	Location     string
+	Mentions     []Mention
 }
 
 type PublishAction struct {
@@ -65,30 +78,36 @@ func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
 	}, nil
 }
 
-func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) error {
+func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) (*PublishResult, error) {
 	if len(content.ImagePaths) == 0 {
-		return errors.New("图片不能为空")
+		return nil, errors.New("图片不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
 	if err := uploadImages(page, content.ImagePaths); err != nil {
-		return errors.Wrap(err, "小红书上传图片失败")
+		return nil, errors.Wrap(err, "小红书上传图片失败")
 	}
 
-	tags := content.Tags
-	if len(tags) >= 10 {
-		logrus.Warnf("标签数量超过10，截取前10个标签")
-		tags = tags[:10]
-	}
+	logrus.Infof("发布内容: title=%s, images=%v, tags=%v, schedule=%v, draft=%v, visibility=%s", content.Title, len(content.ImagePaths), content.Tags, content.ScheduleTime, content.Draft, content.Visibility)
 
-	logrus.Infof("发布内容: title=%s, images=%v, tags=%v, schedule=%v", content.Title, len(content.ImagePaths), tags, content.ScheduleTime)
-
-	if err := submitPublish(page, content.Title, content.Content, tags, content.ScheduleTime); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	result, err := submitPublish(page, content.Title, content.Content, content.Tags, publishOptions{
+		ScheduleTime: content.ScheduleTime,
+		Draft:        content.Draft,
+		Visibility:   content.Visibility,
+		Location:     content.Location,
+		Mentions:     content.Mentions,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
+	}
+	if content.Draft {
+		return result, nil
 	}
 
-	return nil
+	resolveUnknownNoteID(ctx, p.page, result, content.Title)
+	resolveNoteXsecToken(ctx, p.page, result)
+	return result, nil
 }
 
 func removePopCover(page *rod.Page) {
@@ -199,6 +218,11 @@ func isElementBlocked(elem *rod.Element) (bool, error) {
 }
 
 func uploadImages(page *rod.Page, imagesPaths []string) error {
+	return appendImages(page, imagesPaths, 0)
+}
+
+// appendImages 在已有 existing 张图片之后继续上传图片
+func appendImages(page *rod.Page, imagesPaths []string, existing int) error {
 	// 验证文件路径有效性
 	validPaths := make([]string, 0, len(imagesPaths))
 	for _, path := range imagesPaths {
@@ -212,8 +236,10 @@ func uploadImages(page *rod.Page, imagesPaths []string) error {
 
 	// 逐张上传：每张上传后等待预览出现，再上传下一张
 	for i, path := range validPaths {
+		ReportProgress(page.GetContext(), StepUploading, existing+i+1, existing+len(validPaths))
+
 		selector := `input[type="file"]`
-		if i == 0 {
+		if i == 0 && existing == 0 {
 			selector = ".upload-input"
 		}
 
@@ -227,8 +253,8 @@ func uploadImages(page *rod.Page, imagesPaths []string) error {
 
 		slog.Info("图片已提交上传", "index", i+1, "path", path)
 
-		// 等待当前图片上传完成（预览元素数量达到 i+1），最多等 60 秒
-		if err := waitForUploadComplete(page, i+1); err != nil {
+		// 等待当前图片上传完成（预览元素数量达到 existing+i+1），最多等 60 秒
+		if err := waitForUploadComplete(page, existing+i+1); err != nil {
 			return errors.Wrapf(err, "第%d张图片上传超时", i+1)
 		}
 		time.Sleep(1 * time.Second)
@@ -268,19 +294,77 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 	return errors.Errorf("第%d张图片上传超时(60s)，请检查网络连接和图片大小", expectedCount)
 }
 
-func submitPublish(page *rod.Page, title, content string, tags []string, scheduleTime *time.Time) error {
+func submitPublish(page *rod.Page, title, content string, tags []string, opts publishOptions) (*PublishResult, error) {
+	tagResults, err := fillNoteForm(page, title, content, tags, opts.Mentions, false)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := applyPublishSettings(page, opts)
+	if err != nil {
+		return nil, err
+	}
+	applied.Tags = tagResults
+
+	var result *PublishResult
+	if opts.Draft {
+		ReportProgress(page.GetContext(), StepSubmitting, 0, 0)
+		result, err = saveDraftAndWait(page)
+	} else {
+		if err := applyScheduleTime(page, opts.ScheduleTime); err != nil {
+			return nil, err
+		}
+		ReportProgress(page.GetContext(), StepSubmitting, 0, 0)
+
+		submitButton, err := page.Element(".publish-page-publish-btn button.bg-red")
+		if err != nil {
+			return nil, errors.Wrap(err, "查找发布按钮失败")
+		}
+
+		result, err = clickPublishAndWait(page, submitButton)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	applied.applyTo(result)
+	return result, nil
+}
+
+// applyScheduleTime 处理定时发布，scheduleTime 为 nil 时不做任何操作
+func applyScheduleTime(page *rod.Page, scheduleTime *time.Time) error {
+	if scheduleTime == nil {
+		return nil
+	}
+	if err := setSchedulePublish(page, *scheduleTime); err != nil {
+		return errors.Wrap(err, "设置定时发布失败")
+	}
+	slog.Info("定时发布设置完成", "schedule_time", scheduleTime.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// fillNoteForm 填写标题、正文（含 @ 用户）和标签并检查长度，返回每个标签的处理结果。
+// replace 为 true 时先清空编辑器中已有的内容（编辑已发布笔记时使用）。
+func fillNoteForm(page *rod.Page, title, content string, tags []string, mentions []Mention, replace bool) ([]TagResult, error) {
+	ReportProgress(page.GetContext(), StepFillingForm, 0, 0)
+
 	titleElem, err := page.Element("div.d-input input")
 	if err != nil {
-		return errors.Wrap(err, "查找标题输入框失败")
+		return nil, errors.Wrap(err, "查找标题输入框失败")
+	}
+	if replace {
+		if err := titleElem.SelectAllText(); err != nil {
+			return nil, errors.Wrap(err, "选择标题文本失败")
+		}
 	}
 	if err := titleElem.Input(title); err != nil {
-		return errors.Wrap(err, "输入标题失败")
+		return nil, errors.Wrap(err, "输入标题失败")
 	}
 
 	// 检查标题长度
 	time.Sleep(500 * time.Millisecond)
 	if err := checkTitleMaxLength(page); err != nil {
-		return err
+		return nil, err
 	}
 	slog.Info("检查标题长度：通过")
 
@@ -288,41 +372,66 @@ func submitPublish(page *rod.Page, title, content string, tags []string, schedul
 
 	contentElem, ok := getContentElement(page)
 	if !ok {
-		return errors.New("没有找到内容输入框")
+		return nil, errors.New("没有找到内容输入框")
 	}
-	if err := contentElem.Input(content); err != nil {
-		return errors.Wrap(err, "输入正文失败")
+	if replace {
+		if err := clearContentElement(contentElem); err != nil {
+			return nil, err
+		}
 	}
-	if err := inputTags(contentElem, tags); err != nil {
-		return err
+	if err := inputWithMentions(contentElem, content, mentions, publishMentionItemSelector); err != nil {
+		return nil, errors.Wrap(err, "输入正文失败")
+	}
+	tagResults, err := inputTags(contentElem, tags)
+	if err != nil {
+		return nil, err
 	}
 
 	time.Sleep(1 * time.Second)
 
 	// 检查正文长度
 	if err := checkContentMaxLength(page); err != nil {
-		return err
+		return nil, err
 	}
 	slog.Info("检查正文长度：通过")
 
-	// 处理定时发布
-	if scheduleTime != nil {
-		if err := setSchedulePublish(page, *scheduleTime); err != nil {
-			return errors.Wrap(err, "设置定时发布失败")
-		}
-		slog.Info("定时发布设置完成", "schedule_time", scheduleTime.Format("2006-01-02 15:04"))
-	}
+	return tagResults, nil
+}
 
-	submitButton, err := page.Element(".publish-page-publish-btn button.bg-red")
+// clearContentElement 清空正文编辑器（包括其中的话题标签）
+func clearContentElement(contentElem *rod.Element) error {
+	_, err := contentElem.Eval(`() => {
+		this.focus();
+		const range = document.createRange();
+		range.selectNodeContents(this);
+		const selection = window.getSelection();
+		selection.removeAllRanges();
+		selection.addRange(range);
+		document.execCommand('delete');
+	}`)
 	if err != nil {
-		return errors.Wrap(err, "查找发布按钮失败")
+		return errors.Wrap(err, "清空正文失败")
 	}
+	time.Sleep(300 * time.Millisecond)
+	return nil
+}
+
+// clickPublishAndWait 点击发布按钮并等待发布结果
+func clickPublishAndWait(page *rod.Page, submitButton *rod.Element) (*PublishResult, error) {
+	watcher := watchPublishResponse(page)
+
 	if err := submitButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击发布按钮失败")
+		watcher.Stop()
+		return nil, errors.Wrap(err, "点击发布按钮失败")
 	}
 
-	time.Sleep(3 * time.Second)
-	return nil
+	result, err := waitForPublishResult(page, watcher)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("发布完成", "note_id", result.NoteID, "url", result.URL)
+	return result, nil
 }
 
 // 检查标题是否超过最大长度
@@ -403,9 +512,16 @@ func getContentElement(page *rod.Page) (*rod.Element, bool) {
 	return nil, false
 }
 
-func inputTags(contentElem *rod.Element, tags []string) error {
-	if len(tags) == 0 {
-		return nil
+// inputTags 在正文末尾输入话题标签，返回每个请求标签的处理结果（与 tags 顺序一致）
+func inputTags(contentElem *rod.Element, tags []string) ([]TagResult, error) {
+	results := planTags(tags)
+	if len(results) == 0 {
+		return nil, nil
+	}
+	for _, r := range results {
+		if r.Status == TagStatusDropped {
+			logrus.Warnf("标签[%s]未输入: %s", r.Tag, r.Reason)
+		}
 	}
 
 	time.Sleep(1 * time.Second)
@@ -413,34 +529,38 @@ func inputTags(contentElem *rod.Element, tags []string) error {
 	for i := 0; i < 20; i++ {
 		ka, err := contentElem.KeyActions()
 		if err != nil {
-			return errors.Wrap(err, "创建键盘操作失败")
+			return nil, errors.Wrap(err, "创建键盘操作失败")
 		}
 		if err := ka.Type(input.ArrowDown).Do(); err != nil {
-			return errors.Wrap(err, "按下方向键失败")
+			return nil, errors.Wrap(err, "按下方向键失败")
 		}
 		time.Sleep(10 * time.Millisecond)
 	}
 
 	ka, err := contentElem.KeyActions()
 	if err != nil {
-		return errors.Wrap(err, "创建键盘操作失败")
+		return nil, errors.Wrap(err, "创建键盘操作失败")
 	}
 	if err := ka.Press(input.Enter).Press(input.Enter).Do(); err != nil {
-		return errors.Wrap(err, "按下回车键失败")
+		return nil, errors.Wrap(err, "按下回车键失败")
 	}
 
 	time.Sleep(1 * time.Second)
 
-	for _, tag := range tags {
-		tag = strings.TrimLeft(tag, "#")
-		if err := inputTag(contentElem, tag); err != nil {
-			return errors.Wrapf(err, "输入标签[%s]失败", tag)
+	for i := range results {
+		if results[i].Status != "" {
+			continue
+		}
+		if err := inputTag(contentElem, &results[i]); err != nil {
+			return nil, errors.Wrapf(err, "输入标签[%s]失败", results[i].Tag)
 		}
 	}
-	return nil
+	return results, nil
 }
 
-func inputTag(contentElem *rod.Element, tag string) error {
+// inputTag 输入 #标签 并从联想框中选择话题（优先同名话题），没有联想时作为普通文本输入
+func inputTag(contentElem *rod.Element, result *TagResult) error {
+	tag := result.Tag
 	if err := contentElem.Input("#"); err != nil {
 		return errors.Wrap(err, "输入#失败")
 	}
@@ -456,25 +576,25 @@ func inputTag(contentElem *rod.Element, tag string) error {
 	time.Sleep(1 * time.Second)
 
 	page := contentElem.Page()
-	topicContainer, err := page.Element("#creator-editor-topic-container")
-	if err != nil || topicContainer == nil {
-		slog.Warn("未找到标签联想下拉框，直接输入空格", "tag", tag)
-		return contentElem.Input(" ")
-	}
-
-	firstItem, err := topicContainer.Element(".item")
-	if err != nil || firstItem == nil {
+	topics, err := readTopicSuggestions(page)
+	if err != nil || len(topics) == 0 {
 		slog.Warn("未找到标签联想选项，直接输入空格", "tag", tag)
+		result.Status = TagStatusPlain
+		result.Reason = "没有联想到话题"
 		return contentElem.Input(" ")
 	}
 
-	if err := firstItem.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击标签联想选项失败")
+	index := chooseTopic(tag, topics)
+	if err := clickTopicSuggestion(page, index); err != nil {
+		return err
 	}
-	slog.Info("成功点击标签联想选项", "tag", tag)
-	time.Sleep(200 * time.Millisecond)
+	slog.Info("成功点击标签联想选项", "tag", tag, "topic", topics[index].Name)
+
+	topic := topics[index]
+	result.Status = TagStatusTopic
+	result.Topic = &topic
 
-	time.Sleep(500 * time.Millisecond) // 等待标签处理完成
+	time.Sleep(700 * time.Millisecond) // 等待标签处理完成
 	return nil
 }
 
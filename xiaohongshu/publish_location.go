@@ -0,0 +1,177 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// POI 发布笔记时可选的地点
+type POI struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+}
+
+// LocationNotMatchedError 地点搜索结果中没有与关键词匹配的地点
+type LocationNotMatchedError struct {
+	Query      string
+	Candidates []POI
+}
+
+func (e *LocationNotMatchedError) Error() string {
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("没有搜索到地点: %s", e.Query)
+	}
+
+	names := make([]string, 0, len(e.Candidates))
+	for _, c := range e.Candidates {
+		if c.Address != "" {
+			names = append(names, fmt.Sprintf("%s（%s）", c.Name, c.Address))
+		} else {
+			names = append(names, c.Name)
+		}
+	}
+	return fmt.Sprintf("没有与[%s]匹配的地点，候选地点: %s", e.Query, strings.Join(names, "；"))
+}
+
+// minPOIScore 低于该分数的候选地点不会被自动选择
+const minPOIScore = 0.6
+
+const poiOptionSelector = `.d-select-dropdown .d-option, .d-dropdown .d-option, [class*="poi"] [class*="item"], [class*="location"] [class*="item"]`
+
+// setLocation 在发布表单的地点选择器中搜索并选择与 query 最匹配的地点
+func setLocation(page *rod.Page, query string) (*POI, error) {
+	query = strings.TrimSpace(query)
+
+	if !clickByText(page, ".publish-page-content-settings, .post-settings, body", "添加地点", "添加位置") {
+		return nil, errors.New("没有找到添加地点入口")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	searchInput, err := page.Timeout(5 * time.Second).Element(`.d-select-dropdown input, [class*="poi"] input, input[placeholder*="地点"], input[placeholder*="位置"]`)
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到地点搜索框")
+	}
+	if err := searchInput.Input(query); err != nil {
+		return nil, errors.Wrap(err, "输入地点关键词失败")
+	}
+
+	candidates, err := waitForPOICandidates(page, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	index, ok := chooseBestPOI(query, candidates)
+	if !ok {
+		// 关闭下拉框，避免影响后续操作
+		clickEmptyPosition(page)
+		return nil, &LocationNotMatchedError{Query: query, Candidates: candidates}
+	}
+
+	result, err := page.Eval(`(selector, index) => {
+		const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		});
+		if (index >= items.length) return false;
+		items[index].click();
+		return true;
+	}`, poiOptionSelector, index)
+	if err != nil || !result.Value.Bool() {
+		return nil, errors.New("点击地点选项失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	poi := candidates[index]
+	slog.Info("已添加地点", "query", query, "name", poi.Name, "address", poi.Address)
+	return &poi, nil
+}
+
+// waitForPOICandidates 等待地点搜索结果出现
+func waitForPOICandidates(page *rod.Page, timeout time.Duration) ([]POI, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(800 * time.Millisecond)
+
+		result, err := page.Eval(`(selector) => {
+			const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+				const rect = el.getBoundingClientRect();
+				return rect.width > 0 && rect.height > 0;
+			});
+			return JSON.stringify(items.map(el => {
+				const lines = (el.innerText || '').split('\n').map(s => s.trim()).filter(Boolean);
+				return {name: lines[0] || '', address: lines.slice(1).join(' ')};
+			}));
+		}`, poiOptionSelector)
+		if err != nil {
+			continue
+		}
+
+		var candidates []POI
+		if err := json.Unmarshal([]byte(result.Value.String()), &candidates); err != nil {
+			continue
+		}
+		if len(candidates) > 0 {
+			return candidates, nil
+		}
+	}
+
+	return nil, errors.New("等待地点搜索结果超时")
+}
+
+// chooseBestPOI 选出与关键词最匹配的地点，分数不足时返回 false
+func chooseBestPOI(query string, candidates []POI) (int, bool) {
+	best, bestScore := -1, 0.0
+	for i, c := range candidates {
+		score := poiMatchScore(query, c)
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, best >= 0 && bestScore >= minPOIScore
+}
+
+// poiMatchScore 计算关键词与地点的匹配程度（0~1）
+func poiMatchScore(query string, poi POI) float64 {
+	q := normalizePOIText(query)
+	name := normalizePOIText(poi.Name)
+	if q == "" || name == "" {
+		return 0
+	}
+
+	switch {
+	case q == name:
+		return 1
+	case strings.Contains(name, q):
+		// 关键词是地点名称的一部分，如 "星巴克" 与 "星巴克(国贸店)"
+		return 0.8 + 0.1*float64(len([]rune(q)))/float64(len([]rune(name)))
+	case strings.Contains(q, name):
+		return 0.7
+	case strings.Contains(normalizePOIText(poi.Name+poi.Address), q):
+		return 0.65
+	}
+
+	// 按字符重合率打分
+	nameRunes := make(map[rune]int)
+	for _, r := range name {
+		nameRunes[r]++
+	}
+	common := 0
+	for _, r := range q {
+		if nameRunes[r] > 0 {
+			nameRunes[r]--
+			common++
+		}
+	}
+	return 0.6 * float64(common) / float64(len([]rune(q)))
+}
+
+func normalizePOIText(s string) string {
+	replacer := strings.NewReplacer(" ", "", "（", "(", "）", ")", "·", "", "-", "")
+	return strings.ToLower(replacer.Replace(strings.TrimSpace(s)))
+}
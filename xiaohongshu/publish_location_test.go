@@ -0,0 +1,46 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseBestPOI(t *testing.T) {
+	candidates := []POI{
+		{Name: "星巴克(国贸三期店)", Address: "北京市朝阳区建国门外大街"},
+		{Name: "星巴克臻选(国贸商城店)", Address: "北京市朝阳区"},
+		{Name: "国贸商城", Address: "北京市朝阳区建国门外大街1号"},
+	}
+
+	t.Run("完全匹配", func(t *testing.T) {
+		index, ok := chooseBestPOI("国贸商城", candidates)
+		assert.True(t, ok)
+		assert.Equal(t, 2, index)
+	})
+
+	t.Run("关键词是名称的一部分", func(t *testing.T) {
+		index, ok := chooseBestPOI("星巴克 国贸三期店", candidates)
+		assert.True(t, ok)
+		assert.Equal(t, 0, index)
+	})
+
+	t.Run("没有匹配的地点", func(t *testing.T) {
+		_, ok := chooseBestPOI("上海外滩", candidates)
+		assert.False(t, ok)
+	})
+
+	t.Run("没有候选地点", func(t *testing.T) {
+		_, ok := chooseBestPOI("国贸", nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestLocationNotMatchedError(t *testing.T) {
+	err := &LocationNotMatchedError{
+		Query:      "上海外滩",
+		Candidates: []POI{{Name: "外滩源", Address: "上海市黄浦区"}, {Name: "外白渡桥"}},
+	}
+	assert.Contains(t, err.Error(), "外滩源（上海市黄浦区）")
+	assert.Contains(t, err.Error(), "外白渡桥")
+}
@@ -0,0 +1,241 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PublishResult 发布结果
+type PublishResult struct {
+	NoteID        string      `json:"note_id"`
+	URL           string      `json:"url"`
+	XsecToken     string      `json:"xsec_token,omitempty"`
+	NoteIDUnknown bool        `json:"note_id_unknown,omitempty"` // 已发布成功，但没能拿到笔记 ID
+	Location      *POI        `json:"location,omitempty"`        // 实际添加的地点
+	Tags          []TagResult `json:"tags,omitempty"`            // 每个请求标签的处理结果
+}
+
+// ErrSubmitUnconfirmed 已经点击了发布或保存草稿，但没有等到结果，笔记可能已经发布，不能直接重试
+var ErrSubmitUnconfirmed = errors.New("已提交，但无法确认结果")
+
+const (
+	// publishNoteAPIPath 创作者中心发布笔记的接口路径
+	publishNoteAPIPath = "/web_api/sns/v2/note"
+
+	publishResultTimeout = 60 * time.Second
+
+	// publishErrorToastSelector 创作者中心的错误提示，只认带错误类型的提示，
+	// 普通提示和成功提示不算发布失败
+	publishErrorToastSelector = `.d-toast.error, .d-toast--error, .d-toast-error, .d-message--error, .d-message.error, .el-message--error, .creator-toast.error`
+)
+
+// watchPublishResponse 在点击发布之前调用，开始监听发布接口的响应
+func watchPublishResponse(page *rod.Page) *apiResponseWatcher {
+	return watchAPIResponses(page, "POST", publishNoteAPIPath)
+}
+
+// waitForPublishResult 点击发布后等待发布结果：
+// 优先使用发布接口的响应，其次检测错误提示和发布成功页面。
+// 除了接口明确返回失败之外，点击之后发现的问题都包装为 ErrSubmitUnconfirmed，
+// 因为笔记可能已经发布，不能直接重试
+func waitForPublishResult(page *rod.Page, w *apiResponseWatcher) (*PublishResult, error) {
+	defer w.Stop()
+
+	deadline := time.Now().Add(publishResultTimeout)
+	var successSince time.Time
+
+	for time.Now().Before(deadline) {
+		select {
+		case resp := <-w.C:
+			return parsePublishResponse(resp.Body)
+		default:
+		}
+
+		if msg := findPublishErrorToast(page); msg != "" {
+			return nil, errors.Wrapf(ErrSubmitUnconfirmed, "点击发布后出现错误提示: %s，请到创作者中心确认笔记是否已发布", msg)
+		}
+
+		if successSince.IsZero() && isPublishSuccessPage(page) {
+			logrus.Info("检测到发布成功页面，等待发布接口响应")
+			successSince = time.Now()
+		}
+
+		// 已到达成功页面但迟迟拿不到接口响应，视为成功但笔记 ID 未知，之后再到笔记管理页查找
+		if !successSince.IsZero() && time.Since(successSince) > 5*time.Second {
+			logrus.Warn("发布成功，但未捕获到发布接口响应，暂时无法获取笔记 ID")
+			return &PublishResult{NoteIDUnknown: true}, nil
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return nil, errors.Wrap(ErrSubmitUnconfirmed, "等待发布结果超时，请到创作者中心确认笔记是否已发布")
+}
+
+// publishAPIResponse 创作者中心发布接口的响应
+type publishAPIResponse struct {
+	Success   bool   `json:"success"`
+	Code      int    `json:"code"`
+	Msg       string `json:"msg"`
+	ShareLink string `json:"share_link"`
+	Data      struct {
+		ID        string `json:"id"`
+		NoteID    string `json:"note_id"`
+		ShareLink string `json:"share_link"`
+	} `json:"data"`
+}
+
+// parsePublishResponse 解析发布接口响应，提取笔记 ID 和分享链接。
+// 只有接口明确返回 success=false 时才是确定的发布失败
+func parsePublishResponse(body []byte) (*PublishResult, error) {
+	var resp publishAPIResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(ErrSubmitUnconfirmed, "解析发布接口响应失败，请到创作者中心确认笔记是否已发布")
+	}
+
+	if !resp.Success {
+		msg := resp.Msg
+		if msg == "" {
+			msg = fmt.Sprintf("code=%d", resp.Code)
+		}
+		return nil, errors.Errorf("发布失败: %s", msg)
+	}
+
+	noteID := resp.Data.ID
+	if noteID == "" {
+		noteID = resp.Data.NoteID
+	}
+
+	shareLink := resp.ShareLink
+	if shareLink == "" {
+		shareLink = resp.Data.ShareLink
+	}
+	if shareLink == "" && noteID != "" {
+		shareLink = makeNoteURL(noteID)
+	}
+
+	return &PublishResult{
+		NoteID:        noteID,
+		URL:           shareLink,
+		NoteIDUnknown: noteID == "",
+	}, nil
+}
+
+// findPublishErrorToast 查找点击发布后弹出的错误提示
+func findPublishErrorToast(page *rod.Page) string {
+	result, err := page.Eval(`(selector) => {
+		const nodes = document.querySelectorAll(selector);
+		for (const node of nodes) {
+			const rect = node.getBoundingClientRect();
+			if (rect.width === 0 || rect.height === 0) continue;
+			const text = (node.innerText || '').trim();
+			if (text) return text;
+		}
+		return "";
+	}`, publishErrorToastSelector)
+	if err != nil || result == nil {
+		return ""
+	}
+
+	text := strings.TrimSpace(result.Value.String())
+	if text == "" || strings.Contains(text, "成功") {
+		return ""
+	}
+	return text
+}
+
+// isPublishSuccessPage 是否已跳转到发布成功页面
+func isPublishSuccessPage(page *rod.Page) bool {
+	if info, err := page.Info(); err == nil && info != nil && strings.Contains(info.URL, "/publish/success") {
+		return true
+	}
+
+	has, _, err := page.Has(".publish-success, .success-container")
+	return err == nil && has
+}
+
+// resolveUnknownNoteID 发布成功但没拿到笔记 ID 时，到笔记管理页查看最新一条笔记，
+// 标题与刚发布的一致时补上笔记 ID，否则保留 NoteIDUnknown
+func resolveUnknownNoteID(ctx context.Context, page *rod.Page, result *PublishResult, title string) {
+	if result == nil || !result.NoteIDUnknown {
+		return
+	}
+
+	var notes *MyNotesPage
+	err := rod.Try(func() {
+		var err error
+		notes, err = NewCreatorNotesAction(page).ListMyNotes(ctx, ListMyNotesOptions{})
+		if err != nil {
+			panic(err)
+		}
+	})
+	if err != nil {
+		logrus.Warnf("到笔记管理页查找新笔记失败: %v", err)
+		return
+	}
+
+	note, ok := matchLatestNote(notes.Notes, title)
+	if !ok {
+		logrus.Warnf("笔记管理页最新的笔记与刚发布的标题不一致，无法确定笔记 ID: %s", title)
+		return
+	}
+
+	result.NoteID = note.NoteID
+	result.URL = note.URL
+	result.XsecToken = note.XsecToken
+	result.NoteIDUnknown = false
+}
+
+// matchLatestNote 笔记管理页按时间倒序排列，只有最新一条的标题一致时才认为是刚发布的笔记，
+// 避免同名的旧笔记被误认
+func matchLatestNote(notes []MyNote, title string) (MyNote, bool) {
+	title = strings.TrimSpace(title)
+	if len(notes) == 0 || title == "" || notes[0].NoteID == "" {
+		return MyNote{}, false
+	}
+	if strings.TrimSpace(notes[0].Title) != title {
+		return MyNote{}, false
+	}
+	return notes[0], true
+}
+
+// resolveNoteXsecToken 通过个人主页查找新笔记的 xsec_token，获取失败时保持原样
+func resolveNoteXsecToken(ctx context.Context, page *rod.Page, result *PublishResult) {
+	if result == nil || result.NoteID == "" || result.XsecToken != "" {
+		return
+	}
+
+	var profile *UserProfileResponse
+	err := rod.Try(func() {
+		var err error
+		profile, err = NewUserProfileAction(page).GetMyProfileViaSidebar(ctx)
+		if err != nil {
+			panic(err)
+		}
+	})
+	if err != nil {
+		logrus.Warnf("获取笔记 xsec_token 失败: %v", err)
+		return
+	}
+
+	for _, feed := range profile.Feeds {
+		if feed.ID == result.NoteID && feed.XsecToken != "" {
+			result.XsecToken = feed.XsecToken
+			result.URL = makeFeedDetailURL(feed.ID, feed.XsecToken)
+			return
+		}
+	}
+
+	logrus.Warnf("个人主页中暂未找到新笔记 %s，可能仍在审核中", result.NoteID)
+}
+
+func makeNoteURL(noteID string) string {
+	return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s", noteID)
+}
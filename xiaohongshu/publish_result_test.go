@@ -0,0 +1,66 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublishResponse(t *testing.T) {
+	t.Run("成功并返回分享链接", func(t *testing.T) {
+		body := `{"result":0,"success":true,"msg":"","data":{"id":"65a1b2c3000000001e03a4b5","score":10},"share_link":"https://www.xiaohongshu.com/discovery/item/65a1b2c3000000001e03a4b5"}`
+
+		result, err := parsePublishResponse([]byte(body))
+		require.NoError(t, err)
+		assert.Equal(t, "65a1b2c3000000001e03a4b5", result.NoteID)
+		assert.Equal(t, "https://www.xiaohongshu.com/discovery/item/65a1b2c3000000001e03a4b5", result.URL)
+	})
+
+	t.Run("成功但没有分享链接", func(t *testing.T) {
+		body := `{"success":true,"data":{"note_id":"65a1b2c3000000001e03a4b5"}}`
+
+		result, err := parsePublishResponse([]byte(body))
+		require.NoError(t, err)
+		assert.Equal(t, "65a1b2c3000000001e03a4b5", result.NoteID)
+		assert.Equal(t, "https://www.xiaohongshu.com/explore/65a1b2c3000000001e03a4b5", result.URL)
+	})
+
+	t.Run("接口返回失败", func(t *testing.T) {
+		body := `{"success":false,"code":-9101,"msg":"标题包含敏感词"}`
+
+		_, err := parsePublishResponse([]byte(body))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "标题包含敏感词")
+		assert.NotErrorIs(t, err, ErrSubmitUnconfirmed)
+	})
+
+	t.Run("成功但没有笔记ID", func(t *testing.T) {
+		result, err := parsePublishResponse([]byte(`{"success":true,"data":{}}`))
+		require.NoError(t, err)
+		assert.True(t, result.NoteIDUnknown)
+	})
+
+	t.Run("非JSON响应", func(t *testing.T) {
+		_, err := parsePublishResponse([]byte(`<html></html>`))
+		assert.ErrorIs(t, err, ErrSubmitUnconfirmed)
+	})
+}
+
+func TestMatchLatestNote(t *testing.T) {
+	notes := []MyNote{
+		{NoteID: "65a1b2c3000000001e03a4b5", Title: "周末去哪儿"},
+		{NoteID: "65a1b2c3000000001e03a4b4", Title: "今日穿搭"},
+	}
+
+	note, ok := matchLatestNote(notes, " 周末去哪儿 ")
+	require.True(t, ok)
+	assert.Equal(t, "65a1b2c3000000001e03a4b5", note.NoteID)
+
+	// 同名的旧笔记不是最新一条，不能当作刚发布的笔记
+	_, ok = matchLatestNote(notes, "今日穿搭")
+	assert.False(t, ok)
+
+	_, ok = matchLatestNote(nil, "周末去哪儿")
+	assert.False(t, ok)
+}
@@ -0,0 +1,143 @@
+package xiaohongshu
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// 笔记可见范围
+const (
+	VisibilityPublic  = "公开"
+	VisibilityPrivate = "仅自己可见"
+	VisibilityFriends = "仅互关好友可见"
+)
+
+// visibilityOptionTexts 创作者中心可见范围下拉框中各选项的文案
+var visibilityOptionTexts = map[string][]string{
+	VisibilityPublic:  {"公开可见", "公开"},
+	VisibilityPrivate: {"仅自己可见", "私密"},
+	VisibilityFriends: {"仅互关好友可见", "好友可见"},
+}
+
+// visibilityWidgetSelector 发布表单中的可见范围设置
+const visibilityWidgetSelector = `.permission-card-wrapper, [class*="permission-card"]`
+
+// ParseVisibility 解析可见范围，支持中文和 public/private/friends，空字符串视为公开
+func ParseVisibility(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "public", "公开", "公开可见":
+		return VisibilityPublic, nil
+	case "private", "self", "仅自己可见", "私密":
+		return VisibilityPrivate, nil
+	case "friends", "mutual", "仅互关好友可见", "好友可见":
+		return VisibilityFriends, nil
+	}
+	return "", errors.Errorf("不支持的可见范围: %s（支持 公开/仅自己可见/仅互关好友可见）", s)
+}
+
+// appliedSettings 实际生效的发布选项，用于回填发布结果
+type appliedSettings struct {
+	Location *POI
+	Tags     []TagResult
+}
+
+// applyTo 把实际生效的发布选项写入发布结果
+func (s appliedSettings) applyTo(result *PublishResult) {
+	if result == nil {
+		return
+	}
+	result.Location = s.Location
+	result.Tags = s.Tags
+}
+
+// applyPublishSettings 填写完标题正文后、提交之前设置笔记的发布选项
+func applyPublishSettings(page *rod.Page, opts publishOptions) (appliedSettings, error) {
+	var applied appliedSettings
+
+	if err := setVisibility(page, opts.Visibility); err != nil {
+		return applied, errors.Wrap(err, "设置可见范围失败")
+	}
+
+	if strings.TrimSpace(opts.Location) != "" {
+		poi, err := setLocation(page, opts.Location)
+		if err != nil {
+			return applied, errors.Wrap(err, "设置地点失败")
+		}
+		applied.Location = poi
+	}
+
+	return applied, nil
+}
+
+// setVisibility 在发布表单中选择可见范围，公开为默认值不需要设置
+func setVisibility(page *rod.Page, visibility string) error {
+	if visibility == "" || visibility == VisibilityPublic {
+		return nil
+	}
+
+	texts, ok := visibilityOptionTexts[visibility]
+	if !ok {
+		return errors.Errorf("不支持的可见范围: %s", visibility)
+	}
+
+	// 点开可见范围下拉框（当前显示为"公开可见"），只在可见范围设置中查找，避免点到正文等其他位置的同名文字
+	if !clickByText(page, visibilityWidgetSelector, visibilityOptionTexts[VisibilityPublic]...) {
+		return errors.New("没有找到可见范围设置")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if !clickByText(page, ".d-dropdown, .d-popover, .d-select-dropdown, [class*=dropdown], [class*=options]", texts...) {
+		return errors.Errorf("没有找到可见范围选项: %s", visibility)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	// 读取设置中显示的可见范围，确认选中的是要设置的选项
+	displayed := displayedVisibility(page)
+	if !visibilityMatches(displayed, visibility) {
+		return errors.Errorf("可见范围设置后显示为「%s」，与要设置的「%s」不一致", displayed, visibility)
+	}
+
+	slog.Info("已设置可见范围", "visibility", visibility)
+	return nil
+}
+
+// displayedVisibility 可见范围设置中当前显示的文字
+func displayedVisibility(page *rod.Page) string {
+	result, err := page.Eval(`(selector) => {
+		const widget = Array.from(document.querySelectorAll(selector)).find(el => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		});
+		return widget ? (widget.innerText || '').trim() : '';
+	}`, visibilityWidgetSelector)
+	if err != nil || result == nil {
+		return ""
+	}
+	return result.Value.String()
+}
+
+// visibilityMatches 显示的文字是否为 visibility 对应的选项，同时出现其他选项的文字时视为不一致
+func visibilityMatches(displayed, visibility string) bool {
+	matches := func(v string) bool {
+		for _, text := range visibilityOptionTexts[v] {
+			if strings.Contains(displayed, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !matches(visibility) {
+		return false
+	}
+	for v := range visibilityOptionTexts {
+		if v != visibility && matches(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: VisibilityPublic},
+		{input: "public", want: VisibilityPublic},
+		{input: "公开", want: VisibilityPublic},
+		{input: "Private", want: VisibilityPrivate},
+		{input: "仅自己可见", want: VisibilityPrivate},
+		{input: "friends", want: VisibilityFriends},
+		{input: "仅互关好友可见", want: VisibilityFriends},
+		{input: "粉丝可见", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVisibility(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVisibilityMatches(t *testing.T) {
+	assert.True(t, visibilityMatches("仅自己可见", VisibilityPrivate))
+	assert.True(t, visibilityMatches("可见范围\n仅互关好友可见", VisibilityFriends))
+	assert.False(t, visibilityMatches("公开可见", VisibilityPrivate))
+	assert.False(t, visibilityMatches("公开可见\n仅自己可见\n仅互关好友可见", VisibilityPrivate))
+	assert.False(t, visibilityMatches("", VisibilityFriends))
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -19,7 +18,13 @@ type PublishVideoContent struct {
 	Content      string
 	Tags         []string
 	VideoPath    string
-	ScheduleTime *time.Time // 定时发布时间，nil 表示立即发布
+	ScheduleTime *time.Time     // 定时发布时间，nil 表示立即发布
+	Draft        bool           // 只保存到草稿箱，不发布
+	Visibility   string         // 可见范围，空表示公开
+	Location     string         // 地点关键词，为空不添加地点
+	Mentions     []Mention      // 正文中要 @ 的用户
+	CoverPath    string         // 封面图片本地路径，为空不上传封面
+	CoverOffset  *time.Duration // 按视频时间点截取封面，CoverPath 不为空时忽略
 }
 
 // NewPublishVideoAction 进入发布页并切换到"上传视频"
@@ -51,21 +56,38 @@ func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
 }
 
 // PublishVideo 上传视频并提交
-func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) error {
+func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) (*PublishResult, error) {
 	if content.VideoPath == "" {
-		return errors.New("视频不能为空")
+		return nil, errors.New("视频不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
 	if err := uploadVideo(page, content.VideoPath); err != nil {
-		return errors.Wrap(err, "小红书上传视频失败")
+		return nil, errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideo(page, content.Title, content.Content, content.Tags, content.ScheduleTime); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	if err := setVideoCover(page, content.CoverPath, content.CoverOffset); err != nil {
+		return nil, errors.Wrap(err, "设置视频封面失败")
 	}
-	return nil
+
+	result, err := submitPublishVideo(page, content.Title, content.Content, content.Tags, publishOptions{
+		ScheduleTime: content.ScheduleTime,
+		Draft:        content.Draft,
+		Visibility:   content.Visibility,
+		Location:     content.Location,
+		Mentions:     content.Mentions,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
+	}
+	if content.Draft {
+		return result, nil
+	}
+
+	resolveUnknownNoteID(ctx, p.page, result, content.Title)
+	resolveNoteXsecToken(ctx, p.page, result)
+	return result, nil
 }
 
 // uploadVideo 上传单个本地视频
@@ -87,9 +109,11 @@ func uploadVideo(page *rod.Page, videoPath string) error {
 		}
 	}
 
+	ReportProgress(page.GetContext(), StepUploading, 1, 1)
 	fileInput.MustSetFiles(videoPath)
 
 	// 对于视频，等待发布按钮变为可点击即表示处理完成
+	ReportProgress(page.GetContext(), StepProcessing, 0, 0)
 	btn, err := waitForPublishButtonClickable(pp)
 	if err != nil {
 		return err
@@ -130,50 +154,64 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 }
 
 // submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
-func submitPublishVideo(page *rod.Page, title, content string, tags []string, scheduleTime *time.Time) error {
+func submitPublishVideo(page *rod.Page, title, content string, tags []string, opts publishOptions) (*PublishResult, error) {
+	ReportProgress(page.GetContext(), StepFillingForm, 0, 0)
+
 	// 标题
 	titleElem, err := page.Element("div.d-input input")
 	if err != nil {
-		return errors.Wrap(err, "查找标题输入框失败")
+		return nil, errors.Wrap(err, "查找标题输入框失败")
 	}
 	if err := titleElem.Input(title); err != nil {
-		return errors.Wrap(err, "输入标题失败")
+		return nil, errors.Wrap(err, "输入标题失败")
 	}
 	time.Sleep(1 * time.Second)
 
-	// 正文 + 标签
+	// 正文（含 @ 用户）+ 标签
 	contentElem, ok := getContentElement(page)
 	if !ok {
-		return errors.New("没有找到内容输入框")
+		return nil, errors.New("没有找到内容输入框")
 	}
-	if err := contentElem.Input(content); err != nil {
-		return errors.Wrap(err, "输入正文失败")
+	if err := inputWithMentions(contentElem, content, opts.Mentions, publishMentionItemSelector); err != nil {
+		return nil, errors.Wrap(err, "输入正文失败")
 	}
-	if err := inputTags(contentElem, tags); err != nil {
-		return err
+	tagResults, err := inputTags(contentElem, tags)
+	if err != nil {
+		return nil, err
 	}
 
 	time.Sleep(1 * time.Second)
 
-	// 处理定时发布
-	if scheduleTime != nil {
-		if err := setSchedulePublish(page, *scheduleTime); err != nil {
-			return errors.Wrap(err, "设置定时发布失败")
+	applied, err := applyPublishSettings(page, opts)
+	if err != nil {
+		return nil, err
+	}
+	applied.Tags = tagResults
+
+	if !opts.Draft {
+		if err := applyScheduleTime(page, opts.ScheduleTime); err != nil {
+			return nil, err
 		}
-		slog.Info("定时发布设置完成", "schedule_time", scheduleTime.Format("2006-01-02 15:04"))
 	}
 
-	// 等待发布按钮可点击
+	// 等待发布按钮可点击（视频处理完成），草稿也需要等待视频上传完毕
 	btn, err := waitForPublishButtonClickable(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 点击发布
-	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击发布按钮失败")
+	// 点击发布（或暂存草稿）并等待结果
+	ReportProgress(page.GetContext(), StepSubmitting, 0, 0)
+	var result *PublishResult
+	if opts.Draft {
+		result, err = saveDraftAndWait(page)
+	} else {
+		result, err = clickPublishAndWait(page, btn)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	time.Sleep(3 * time.Second)
-	return nil
+	applied.applyTo(result)
+	return result, nil
 }
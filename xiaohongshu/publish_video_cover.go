@@ -0,0 +1,172 @@
+package xiaohongshu
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// coverModalSelector 视频封面编辑弹窗
+const coverModalSelector = ".d-modal, .cover-modal, [class*=cover-editor], [class*=coverModal]"
+
+// ParseCoverOffset 解析视频封面的截取时间点，支持秒数（"3"、"2.5"）、
+// Go 时长（"1m20s"、"1500ms"）以及 "mm:ss"、"hh:mm:ss"，空字符串返回 nil
+func ParseCoverOffset(s string) (*time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var d time.Duration
+	switch {
+	case strings.Contains(s, ":"):
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return nil, errors.Errorf("封面时间点格式错误: %s", s)
+		}
+		var seconds float64
+		for _, part := range parts {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil || v < 0 {
+				return nil, errors.Errorf("封面时间点格式错误: %s", s)
+			}
+			seconds = seconds*60 + v
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	default:
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			d = time.Duration(v * float64(time.Second))
+		} else if v, err := time.ParseDuration(s); err == nil {
+			d = v
+		} else {
+			return nil, errors.Errorf("封面时间点格式错误: %s（支持 3、2.5、1m20s、01:20）", s)
+		}
+	}
+
+	if d < 0 {
+		return nil, errors.Errorf("封面时间点不能为负数: %s", s)
+	}
+	return &d, nil
+}
+
+// setVideoCover 视频上传完成后在封面编辑弹窗中设置封面：
+// coverPath 不为空时上传封面图片，否则按 offset 截取视频画面
+func setVideoCover(page *rod.Page, coverPath string, offset *time.Duration) error {
+	if coverPath == "" && offset == nil {
+		return nil
+	}
+	ReportProgress(page.GetContext(), StepSettingCover, 0, 0)
+
+	if coverPath != "" {
+		if _, err := os.Stat(coverPath); err != nil {
+			return errors.Wrapf(err, "封面图片不存在: %s", coverPath)
+		}
+	}
+
+	if !clickByText(page, ".upload-content, .cover-container, [class*=cover], body", "设置封面", "修改封面", "编辑封面") {
+		return errors.New("没有找到设置封面入口")
+	}
+	time.Sleep(1 * time.Second)
+
+	if coverPath != "" {
+		if err := uploadCoverImage(page, coverPath); err != nil {
+			return err
+		}
+	} else if err := seekCoverFrame(page, *offset); err != nil {
+		return err
+	}
+
+	if !clickByText(page, coverModalSelector, "确定", "完成", "确认") {
+		return errors.New("没有找到封面确认按钮")
+	}
+
+	// 等待弹窗关闭，封面生成需要一点时间
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		if !isCoverModalOpen(page) {
+			slog.Info("已设置视频封面", "cover", coverPath, "offset", offset)
+			return nil
+		}
+	}
+	return errors.New("等待封面弹窗关闭超时")
+}
+
+// uploadCoverImage 切换到"上传封面"并选择本地图片
+func uploadCoverImage(page *rod.Page, coverPath string) error {
+	clickByText(page, coverModalSelector, "上传封面", "上传图片", "本地上传")
+	time.Sleep(500 * time.Millisecond)
+
+	input, err := page.Timeout(10 * time.Second).Element(`.d-modal input[type=file], [class*=cover] input[type=file], input[type=file][accept*=image]`)
+	if err != nil {
+		return errors.Wrap(err, "没有找到封面上传输入框")
+	}
+	if err := input.SetFiles([]string{coverPath}); err != nil {
+		return errors.Wrap(err, "上传封面图片失败")
+	}
+
+	// 等待裁剪区域加载出上传的图片
+	time.Sleep(2 * time.Second)
+	return nil
+}
+
+// seekCoverFrame 在"截取封面"中把视频定位到 offset 处的画面
+func seekCoverFrame(page *rod.Page, offset time.Duration) error {
+	clickByText(page, coverModalSelector, "截取封面", "视频帧")
+	time.Sleep(500 * time.Millisecond)
+
+	result, err := page.Eval(`(scope, seconds) => new Promise((resolve) => {
+		const roots = Array.from(document.querySelectorAll(scope));
+		const video = roots.map(r => r.querySelector('video')).find(Boolean);
+		if (!video) {
+			resolve({ok: false, reason: 'no-video'});
+			return;
+		}
+		setTimeout(() => resolve({ok: false, reason: 'timeout'}), 10000);
+		const seek = () => {
+			if (seconds > video.duration) {
+				resolve({ok: false, reason: 'out-of-range', duration: video.duration});
+				return;
+			}
+			video.pause();
+			video.addEventListener('seeked', () => resolve({ok: true, duration: video.duration}), {once: true});
+			video.currentTime = seconds;
+			setTimeout(() => resolve({ok: true, duration: video.duration}), 3000);
+		};
+		if (video.readyState >= 1) seek();
+		else video.addEventListener('loadedmetadata', seek, {once: true});
+	})`, coverModalSelector, offset.Seconds())
+	if err != nil {
+		return errors.Wrap(err, "定位封面画面失败")
+	}
+
+	obj := result.Value
+	if !obj.Get("ok").Bool() {
+		if obj.Get("reason").Str() == "out-of-range" {
+			return errors.Errorf("封面时间点 %s 超过视频时长 %.1f 秒", offset, obj.Get("duration").Num())
+		}
+		if obj.Get("reason").Str() == "timeout" {
+			return errors.New("等待封面视频加载超时")
+		}
+		return errors.New("封面弹窗中没有找到视频")
+	}
+	time.Sleep(500 * time.Millisecond)
+	return nil
+}
+
+// isCoverModalOpen 封面编辑弹窗是否仍然可见
+func isCoverModalOpen(page *rod.Page) bool {
+	result, err := page.Eval(`(scope) => Array.from(document.querySelectorAll(scope)).some(el => {
+		const rect = el.getBoundingClientRect();
+		return rect.width > 0 && rect.height > 0 && /封面/.test(el.innerText || '');
+	})`, coverModalSelector)
+	if err != nil {
+		return false
+	}
+	return result.Value.Bool()
+}
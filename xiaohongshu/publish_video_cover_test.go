@@ -0,0 +1,49 @@
+package xiaohongshu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverOffset(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantNil bool
+		wantErr bool
+	}{
+		{input: "", wantNil: true},
+		{input: "  ", wantNil: true},
+		{input: "3", want: 3 * time.Second},
+		{input: "2.5", want: 2500 * time.Millisecond},
+		{input: "1m20s", want: 80 * time.Second},
+		{input: "1500ms", want: 1500 * time.Millisecond},
+		{input: "01:20", want: 80 * time.Second},
+		{input: "1:02:03", want: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: "00:00:04.5", want: 4500 * time.Millisecond},
+		{input: "-1", wantErr: true},
+		{input: "1:2:3:4", wantErr: true},
+		{input: "a:10", wantErr: true},
+		{input: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCoverOffset(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+)
+
+// maxTags 一篇笔记最多添加的话题标签数
+const maxTags = xhsutil.MaxTags
+
+// topicItemSelector 正文编辑器中输入 # 后弹出的话题联想选项
+const topicItemSelector = "#creator-editor-topic-container .item"
+
+// 话题标签的处理结果
+const (
+	TagStatusTopic   = "topic"   // 已成为话题
+	TagStatusPlain   = "plain"   // 没有联想到话题，作为普通文本输入
+	TagStatusDropped = "dropped" // 没有输入
+)
+
+// Topic 编辑器联想出的话题
+type Topic struct {
+	Name      string `json:"name"`
+	ViewCount string `json:"view_count,omitempty"` // 页面展示的浏览量，如 "1.2亿次浏览"
+}
+
+// TagResult 一个请求标签的处理结果
+type TagResult struct {
+	Tag    string `json:"tag"`
+	Status string `json:"status"`
+	Topic  *Topic `json:"topic,omitempty"`  // 实际选择的话题
+	Reason string `json:"reason,omitempty"` // 没有成为话题的原因
+}
+
+// planTags 按请求顺序生成标签结果：去掉 #、丢弃空标签、重复标签和超出数量的标签。
+// Status 为空的是需要输入的标签。
+func planTags(tags []string) []TagResult {
+	results := make([]TagResult, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	kept := 0
+
+	for _, raw := range tags {
+		tag := xhsutil.NormalizeTag(raw)
+		result := TagResult{Tag: tag}
+
+		switch {
+		case tag == "":
+			result.Tag = raw
+			result.Status = TagStatusDropped
+			result.Reason = "标签为空"
+		case seen[strings.ToLower(tag)]:
+			result.Status = TagStatusDropped
+			result.Reason = "重复的标签"
+		case kept >= maxTags:
+			result.Status = TagStatusDropped
+			result.Reason = "超过10个标签"
+		default:
+			seen[strings.ToLower(tag)] = true
+			kept++
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// chooseTopic 优先选择与标签同名的话题，没有时选择第一个联想结果
+func chooseTopic(tag string, topics []Topic) int {
+	if len(topics) == 0 {
+		return -1
+	}
+	for i, t := range topics {
+		if strings.EqualFold(t.Name, tag) {
+			return i
+		}
+	}
+	return 0
+}
+
+// readTopicSuggestions 读取当前话题联想框中的话题
+func readTopicSuggestions(page *rod.Page) ([]Topic, error) {
+	result, err := page.Eval(`(selector) => {
+		const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		});
+		return JSON.stringify(items.map(el => {
+			const lines = (el.innerText || '').split('\n').map(s => s.trim()).filter(Boolean);
+			const name = (lines[0] || '').replace(/^#/, '').trim();
+			const views = lines.slice(1).find(s => /浏览|次|阅读/.test(s)) || '';
+			return {name: name, view_count: views};
+		}));
+	}`, topicItemSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取话题联想失败")
+	}
+
+	var topics []Topic
+	if err := json.Unmarshal([]byte(result.Value.String()), &topics); err != nil {
+		return nil, errors.Wrap(err, "解析话题联想失败")
+	}
+	return topics, nil
+}
+
+// clickTopicSuggestion 点击话题联想框中的第 index 个话题
+func clickTopicSuggestion(page *rod.Page, index int) error {
+	result, err := page.Eval(`(selector, index) => {
+		const items = Array.from(document.querySelectorAll(selector)).filter(el => {
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		});
+		if (index >= items.length) return false;
+		items[index].click();
+		return true;
+	}`, topicItemSelector, index)
+	if err != nil {
+		return errors.Wrap(err, "点击标签联想选项失败")
+	}
+	if !result.Value.Bool() {
+		return errors.New("标签联想选项已消失")
+	}
+	return nil
+}
+
+// SuggestTopics 在发布页编辑器中输入 #关键词，返回联想出的话题，不会发布或保存笔记。
+// 图文编辑器需要先上传图片才会出现，这里上传一张临时的空白图片。
+func (p *PublishAction) SuggestTopics(ctx context.Context, keyword string) ([]Topic, error) {
+	keyword = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(keyword), "#"))
+	if keyword == "" {
+		return nil, errors.New("关键词不能为空")
+	}
+
+	page := p.page.Context(ctx)
+
+	placeholder, err := writePlaceholderImage()
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(placeholder)
+
+	if err := uploadImages(page, []string{placeholder}); err != nil {
+		return nil, errors.Wrap(err, "上传临时图片失败")
+	}
+
+	contentElem, ok := getContentElement(page)
+	if !ok {
+		return nil, errors.New("没有找到内容输入框")
+	}
+
+	if err := contentElem.Input("#"); err != nil {
+		return nil, errors.Wrap(err, "输入#失败")
+	}
+	time.Sleep(200 * time.Millisecond)
+	for _, char := range keyword {
+		if err := contentElem.Input(string(char)); err != nil {
+			return nil, errors.Wrapf(err, "输入字符[%c]失败", char)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var topics []Topic
+	deadline := time.Now().Add(8 * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(800 * time.Millisecond)
+		topics, err = readTopicSuggestions(page)
+		if err == nil && len(topics) > 0 {
+			break
+		}
+	}
+
+	// 关闭联想框，清空输入的内容
+	if ka, err := contentElem.KeyActions(); err == nil {
+		_ = ka.Press(input.Escape).Do()
+	}
+	if err := clearContentElement(contentElem); err != nil {
+		logrus.Warnf("清空编辑器失败: %v", err)
+	}
+
+	slog.Info("获取话题联想", "keyword", keyword, "count", len(topics))
+	if topics == nil {
+		topics = []Topic{}
+	}
+	return topics, nil
+}
+
+// writePlaceholderImage 生成一张 3:4 的白色临时图片，返回文件路径
+func writePlaceholderImage() (string, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	f, err := os.CreateTemp("", "xhs-topic-*.png")
+	if err != nil {
+		return "", errors.Wrap(err, "创建临时图片失败")
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		os.Remove(f.Name())
+		return "", errors.Wrap(err, "写入临时图片失败")
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,52 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanTags(t *testing.T) {
+	results := planTags([]string{"#美食", "旅行", " ", "美食", "##生活 "})
+
+	require.Len(t, results, 5)
+	assert.Equal(t, TagResult{Tag: "美食"}, results[0])
+	assert.Equal(t, TagResult{Tag: "旅行"}, results[1])
+	assert.Equal(t, TagStatusDropped, results[2].Status)
+	assert.Equal(t, "标签为空", results[2].Reason)
+	assert.Equal(t, TagStatusDropped, results[3].Status)
+	assert.Equal(t, "重复的标签", results[3].Reason)
+	assert.Equal(t, TagResult{Tag: "生活"}, results[4])
+}
+
+func TestPlanTagsLimit(t *testing.T) {
+	var tags []string
+	for i := 1; i <= 12; i++ {
+		tags = append(tags, fmt.Sprintf("标签%d", i))
+	}
+
+	results := planTags(tags)
+
+	require.Len(t, results, 12)
+	for i, r := range results {
+		if i < maxTags {
+			assert.Empty(t, r.Status, r.Tag)
+		} else {
+			assert.Equal(t, TagStatusDropped, r.Status, r.Tag)
+			assert.Equal(t, "超过10个标签", r.Reason)
+		}
+	}
+}
+
+func TestChooseTopic(t *testing.T) {
+	topics := []Topic{
+		{Name: "美食探店", ViewCount: "3.2亿次浏览"},
+		{Name: "美食", ViewCount: "100亿次浏览"},
+	}
+
+	assert.Equal(t, 1, chooseTopic("美食", topics))
+	assert.Equal(t, 0, chooseTopic("美食分享", topics))
+	assert.Equal(t, -1, chooseTopic("美食", nil))
+}
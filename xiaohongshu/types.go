@@ -136,6 +136,14 @@ type Comment struct {
 	ShowTags        []string  `json:"showTags"`
 }
 
+// LoginUserInfo 当前登录账号信息，来自 __INITIAL_STATE__.user.userInfo
+type LoginUserInfo struct {
+	UserID   string `json:"userId"`
+	Nickname string `json:"nickname"`
+	RedID    string `json:"redId"`
+	Avatar   string `json:"images"`
+}
+
 // UserProfileResponse 用户详情页完整响应
 type UserProfileResponse struct {
 	UserBasicInfo UserBasicInfo      `json:"userBasicInfo"`